@@ -2,9 +2,9 @@
 package Quadkeys
 
 import (
-	"bytes"
+	"encoding/json"
+	"fmt"
 	"math"
-	"strconv"
 )
 
 // Source: https://msdn.microsoft.com/en-us/library/bb259689.aspx
@@ -16,9 +16,10 @@ const MinLongitude = -180
 const MaxLongitude = -1 * MinLongitude
 const MaxLevel = 23
 
-func init() {
-	// initialization code here
-}
+/// <summary>
+/// The width and height of a tile, in pixels.
+/// </summary>
+const TileSize = 256
 
 /// <summary>
 /// Clips a number to the specified minimum and maximum values.
@@ -39,7 +40,81 @@ func clip(n float64, minValue float64, maxValue float64) float64 {
 /// to 23 (highest detail).</param>
 /// <returns>The map width and height in pixels.</returns>
 func MapSize(levelOfDetail uint) uint {
-	return 256 << levelOfDetail
+	return TileSize << levelOfDetail
+}
+
+/// <summary>
+/// Checks that a level of detail is within the tile system. Level 0 is
+/// legal: it is the single 256x256 tile covering the whole world, whose
+/// QuadKey is the empty string. Levels above MaxLevel are rejected, since
+/// their pixel coordinates no longer fit the package's arithmetic.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 0 to 23.</param>
+/// <returns>An error if the level is above MaxLevel.</returns>
+func validLevel(levelOfDetail uint) error {
+	if levelOfDetail > MaxLevel {
+		return fmt.Errorf("level of detail %d above the maximum level %d", levelOfDetail, MaxLevel)
+	}
+	return nil
+}
+
+/// <summary>
+/// Determines the map width and height (in pixels) at a specified level
+/// of detail, rejecting levels outside the tile system.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>The map width and height in pixels, and an error if the level
+/// is above MaxLevel.</returns>
+func MapSizeErr(levelOfDetail uint) (uint, error) {
+	if err := validLevel(levelOfDetail); err != nil {
+		return 0, err
+	}
+	return MapSize(levelOfDetail), nil
+}
+
+/// <summary>
+/// Determines the number of tiles along each side of the map at a specified
+/// level of detail.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The map width and height in tiles.</returns>
+func tilesPerSide(levelOfDetail uint) int {
+	return int(MapSize(levelOfDetail) / TileSize)
+}
+
+/// <summary>
+/// Wraps a tile X coordinate onto the map, which is cyclic east-west: X is
+/// taken modulo the number of tiles per side, negative values included, so
+/// -1 becomes the easternmost column.
+/// </summary>
+/// <param name="tileX">Tile X coordinate, possibly off the map.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The tile X coordinate on the map.</returns>
+func NormalizeTileX(tileX int, levelOfDetail uint) int {
+	tileCount := tilesPerSide(levelOfDetail)
+	return (tileX%tileCount + tileCount) % tileCount
+}
+
+/// <summary>
+/// Clamps a tile Y coordinate onto the map. The map does not wrap across
+/// the poles, so rows above the top and below the bottom clamp to the
+/// first and last rows.
+/// </summary>
+/// <param name="tileY">Tile Y coordinate, possibly off the map.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The tile Y coordinate on the map.</returns>
+func ClampTileY(tileY int, levelOfDetail uint) int {
+	if tileY < 0 {
+		return 0
+	}
+	if last := tilesPerSide(levelOfDetail) - 1; tileY > last {
+		return last
+	}
+	return tileY
 }
 
 /// <summary>
@@ -56,6 +131,56 @@ func GroundResolution(latitude float64, levelOfDetail uint) float64 {
 	return math.Cos(latitude*math.Pi/180) * 2 * math.Pi * EarthRadius / float64(MapSize(levelOfDetail))
 }
 
+/// <summary>
+/// Determines the coarsest level of detail whose ground resolution at a
+/// specified latitude is at least as fine as a target resolution.
+/// </summary>
+/// <param name="latitude">Latitude (in degrees) at which to measure the
+/// ground resolution.</param>
+/// <param name="metersPerPixel">Target ground resolution, in meters per pixel.</param>
+/// <returns>The level of detail, from 1 to MaxLevel.</returns>
+func levelForGroundResolution(latitude float64, metersPerPixel float64) uint {
+	levelOfDetail := uint(1)
+	for levelOfDetail < MaxLevel && GroundResolution(latitude, levelOfDetail) > metersPerPixel {
+		levelOfDetail++
+	}
+	return levelOfDetail
+}
+
+/// <summary>
+/// Determines the level of detail whose ground resolution at a specified
+/// latitude is nearest a target resolution, inverting GroundResolution.
+/// Resolution halves with each level, so "nearest" is measured by ratio:
+/// the exact fractional level is rounded to the nearest whole level and
+/// clamped to 1..MaxLevel. A resolution that is not positive, or a NaN
+/// resolution or latitude, has no level and yields 1.
+/// </summary>
+/// <param name="metersPerPixel">Target ground resolution, in meters per pixel.</param>
+/// <param name="latitude">Latitude (in degrees) at which to measure the
+/// ground resolution.</param>
+/// <returns>The level of detail, from 1 to MaxLevel.</returns>
+func LevelForResolution(metersPerPixel float64, latitude float64) uint {
+	if !(metersPerPixel > 0) || math.IsNaN(latitude) {
+		return 1
+	}
+	latitude = clip(latitude, MinLatitude, MaxLatitude)
+	worldMeters := math.Cos(latitude*math.Pi/180) * 2 * math.Pi * EarthRadius
+	return uint(clip(math.Round(math.Log2(worldMeters/(TileSize*metersPerPixel))), 1, MaxLevel))
+}
+
+/// <summary>
+/// Determines the ground size (in meters) of a tile edge at a specified
+/// latitude and level of detail.
+/// </summary>
+/// <param name="latitude">Latitude (in degrees) at which to measure the
+/// tile size.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The tile edge length, in meters.</returns>
+func TileGroundSize(latitude float64, levelOfDetail uint) float64 {
+	return GroundResolution(latitude, levelOfDetail) * TileSize
+}
+
 /// <summary>
 /// Determines the map scale at a specified latitude, level of detail,
 /// and screen resolution.
@@ -70,9 +195,35 @@ func MapScale(latitude float64, levelOfDetail uint, screenDpi uint) float64 {
 	return GroundResolution(latitude, levelOfDetail) * float64(screenDpi) / 0.0254
 }
 
+/// <summary>
+/// Determines the level of detail whose map scale at a specified latitude
+/// and screen resolution is nearest a target 1 : N scale, inverting
+/// MapScale. The scale is converted to a ground resolution and passed to
+/// LevelForResolution, so the level is rounded to the nearest whole level
+/// by ratio, either up or down, and clamped to 1..MaxLevel.
+/// </summary>
+/// <param name="scaleDenominator">Target map scale, as the denominator N of
+/// the ratio 1 : N.</param>
+/// <param name="latitude">Latitude (in degrees) at which to measure the
+/// map scale.</param>
+/// <param name="screenDpi">Resolution of the screen, in dots per inch.</param>
+/// <returns>The level of detail, from 1 to MaxLevel.</returns>
+func LevelForScale(scaleDenominator float64, latitude float64, screenDpi uint) uint {
+	return LevelForResolution(scaleDenominator*0.0254/float64(screenDpi), latitude)
+}
+
 /// <summary>
 /// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
-/// into pixel XY coordinates at a specified level of detail.
+/// into pixel XY coordinates at a specified level of detail, the integer
+/// form of LatLongToPixelXYFloat. The result is the pixel containing the
+/// point: coordinates are truncated, not rounded, so the pixel, and the
+/// tile PixelXYToTileXY derives from it, always contain the point as
+/// bounded by PixelXYToLatLong. Rounding would push
+/// points in the last half pixel of a tile into the next tile. As a
+/// result LatLong -> Pixel -> Tile -> QuadKey is stable at levels 1 to 23
+/// for any point not on a tile boundary: the QuadKey names the tile whose
+/// bounds contain the point. Points on the map's eastern and southern
+/// edges, and points outside the map, are clamped into the last pixel.
 /// </summary>
 /// <param name="latitude">Latitude of the point, in degrees.</param>
 /// <param name="longitude">Longitude of the point, in degrees.</param>
@@ -81,17 +232,59 @@ func MapScale(latitude float64, levelOfDetail uint, screenDpi uint) float64 {
 /// <param name="pixelX">Output parameter receiving the X coordinate in pixels.</param>
 /// <param name="pixelY">Output parameter receiving the Y coordinate in pixels.</param>
 func LatLongToPixelXY(latitude float64, longitude float64, levelOfDetail uint) (pixelX int, pixelY int) {
-	latitude = clip(latitude, MinLatitude, MaxLatitude)
-	longitude = clip(longitude, MinLongitude, MaxLongitude)
+	x, y := LatLongToPixelXYFloat(latitude, longitude, levelOfDetail)
+	mapSize := float64(MapSize(levelOfDetail))
+	pixelX = int(clip(x, 0, mapSize-1))
+	pixelY = int(clip(y, 0, mapSize-1))
 
-	x := (longitude + 180) / 360
-	sinLatitude := math.Sin(latitude * math.Pi / 180)
-	y := 0.5 - math.Log((1+sinLatitude)/(1-sinLatitude))/(4*math.Pi)
+	return
+}
 
-	mapSize := MapSize(levelOfDetail)
-	pixelX = int(clip(x*float64(mapSize)+0.5, 0, float64(mapSize-1)))
-	pixelY = int(clip(y*float64(mapSize)+0.5, 0, float64(mapSize-1)))
+/// <summary>
+/// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
+/// into pixel XY coordinates at a specified level of detail, rejecting
+/// levels outside the tile system. Coordinates are clamped as in
+/// LatLongToPixelXY.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <param name="pixelX">Output parameter receiving the X coordinate in pixels.</param>
+/// <param name="pixelY">Output parameter receiving the Y coordinate in pixels.</param>
+/// <param name="err">Output parameter receiving an error if the level is
+/// above MaxLevel.</param>
+func LatLongToPixelXYErr(latitude float64, longitude float64, levelOfDetail uint) (pixelX int, pixelY int, err error) {
+	if err = validLevel(levelOfDetail); err != nil {
+		return 0, 0, err
+	}
+	pixelX, pixelY = LatLongToPixelXY(latitude, longitude, levelOfDetail)
+	return
+}
 
+/// <summary>
+/// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
+/// into pixel XY coordinates at a specified level of detail, rejecting
+/// coordinates outside the map instead of clamping them onto its edge as
+/// LatLongToPixelXY does.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="pixelX">Output parameter receiving the X coordinate in pixels.</param>
+/// <param name="pixelY">Output parameter receiving the Y coordinate in pixels.</param>
+/// <param name="err">Output parameter receiving an error if the latitude is
+/// outside [MinLatitude, MaxLatitude] or the longitude outside
+/// [MinLongitude, MaxLongitude].</param>
+func LatLongToPixelXYStrict(latitude float64, longitude float64, levelOfDetail uint) (pixelX int, pixelY int, err error) {
+	if !(latitude >= MinLatitude && latitude <= MaxLatitude) {
+		return 0, 0, fmt.Errorf("latitude %v outside [%v, %v]", latitude, MinLatitude, MaxLatitude)
+	}
+	if !(longitude >= MinLongitude && longitude <= MaxLongitude) {
+		return 0, 0, fmt.Errorf("longitude %v outside [%v, %v]", longitude, MinLongitude, MaxLongitude)
+	}
+	pixelX, pixelY = LatLongToPixelXY(latitude, longitude, levelOfDetail)
 	return
 }
 
@@ -116,6 +309,57 @@ func PixelXYToLatLong(pixelX int, pixelY int, levelOfDetail uint) (latitude floa
 	return
 }
 
+/// <summary>
+/// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
+/// into sub-pixel XY coordinates at a specified level of detail, for smooth
+/// marker placement, anti-aliased rendering and interpolation. The result
+/// is unrounded and lies in [0, mapSize]; LatLongToPixelXY is its
+/// integer form, truncated to the containing pixel.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="pixelX">Output parameter receiving the X coordinate in pixels.</param>
+/// <param name="pixelY">Output parameter receiving the Y coordinate in pixels.</param>
+func LatLongToPixelXYFloat(latitude float64, longitude float64, levelOfDetail uint) (pixelX float64, pixelY float64) {
+	latitude = clip(latitude, MinLatitude, MaxLatitude)
+	longitude = clip(longitude, MinLongitude, MaxLongitude)
+
+	x := (longitude + 180) / 360
+	sinLatitude := math.Sin(latitude * math.Pi / 180)
+	y := 0.5 - math.Log((1+sinLatitude)/(1-sinLatitude))/(4*math.Pi)
+
+	mapSize := float64(MapSize(levelOfDetail))
+	pixelX = clip(x*mapSize, 0, mapSize)
+	pixelY = clip(y*mapSize, 0, mapSize)
+
+	return
+}
+
+/// <summary>
+/// Converts unrounded pixel XY coordinates at a specified level of detail
+/// into latitude/longitude WGS-84 coordinates (in degrees). Unlike
+/// PixelXYToLatLong the far map edge (pixel mapSize) is reachable, so tile
+/// corners on the right and bottom of the map resolve exactly.
+/// </summary>
+/// <param name="pixelX">X coordinate of the point, in pixels.</param>
+/// <param name="pixelY">Y coordinates of the point, in pixels.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="latitude">Output parameter receiving the latitude in degrees.</param>
+/// <param name="longitude">Output parameter receiving the longitude in degrees.</param>
+func pixelXYToLatLongFloat(pixelX float64, pixelY float64, levelOfDetail uint) (latitude float64, longitude float64) {
+	mapSize := float64(MapSize(levelOfDetail))
+	x := (clip(pixelX, 0, mapSize) / mapSize) - 0.5
+	y := 0.5 - (clip(pixelY, 0, mapSize) / mapSize)
+
+	latitude = 90 - 360*math.Atan(math.Exp(-y*2*math.Pi))/math.Pi
+	longitude = 360 * x
+
+	return
+}
+
 /// <summary>
 /// Converts pixel XY coordinates into tile XY coordinates of the tile containing
 /// the specified pixel.
@@ -125,8 +369,8 @@ func PixelXYToLatLong(pixelX int, pixelY int, levelOfDetail uint) (latitude floa
 /// <param name="tileX">Output parameter receiving the tile X coordinate.</param>
 /// <param name="tileY">Output parameter receiving the tile Y coordinate.</param>
 func PixelXYToTileXY(pixelX int, pixelY int) (tileX int, tileY int) {
-	tileX = pixelX / 256
-	tileY = pixelY / 256
+	tileX = pixelX / TileSize
+	tileY = pixelY / TileSize
 	return
 }
 
@@ -139,72 +383,507 @@ func PixelXYToTileXY(pixelX int, pixelY int) (tileX int, tileY int) {
 /// <param name="pixelX">Output parameter receiving the pixel X coordinate.</param>
 /// <param name="pixelY">Output parameter receiving the pixel Y coordinate.</param>
 func TileXYToPixelXY(tileX int, tileY int) (pixelX int, pixelY int) {
-	pixelX = tileX * 256
-	pixelY = tileY * 256
+	pixelX = tileX * TileSize
+	pixelY = tileY * TileSize
 	return
 }
 
 /// <summary>
-/// Converts tile XY coordinates into a QuadKey at a specified level of detail.
+/// Determines the pixel extent of a tile, for cropping source imagery. The
+/// minimum is inclusive and the maximum exclusive: the tile covers pixels
+/// minX to maxX-1 and minY to maxY-1, with maxX = minX + TileSize and
+/// maxY = minY + TileSize.
 /// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="minX">Output parameter receiving the first pixel X coordinate.</param>
+/// <param name="minY">Output parameter receiving the first pixel Y coordinate.</param>
+/// <param name="maxX">Output parameter receiving the pixel X coordinate just past the tile.</param>
+/// <param name="maxY">Output parameter receiving the pixel Y coordinate just past the tile.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func QuadKeyToPixelBounds(quadKey string) (minX int, minY int, maxX int, maxY int, err error) {
+	tileX, tileY, _, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	minX, minY = TileXYToPixelXY(tileX, tileY)
+	return minX, minY, minX + TileSize, minY + TileSize, nil
+}
+
+/// <summary>
+/// Appends the QuadKey of a tile at a specified level of detail to a byte
+/// slice, the allocation-free form of TileXYToQuadKey: when dst has room
+/// for levelOfDetail more bytes, nothing is allocated, so a caller reusing
+/// one buffer can build any number of QuadKeys without garbage.
+/// </summary>
+/// <param name="dst">The slice to append to.</param>
 /// <param name="tileX">Tile X coordinate.</param>
 /// <param name="tileY">Tile Y coordinate.</param>
 /// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
 /// to 23 (highest detail).</param>
-/// <returns>A string containing the QuadKey.</returns>
-func TileXYToQuadKey(tileX int, tileY int, levelOfDetail uint) string {
-	var buffer bytes.Buffer
+/// <returns>The extended slice.</returns>
+func AppendQuadKey(dst []byte, tileX int, tileY int, levelOfDetail uint) []byte {
 	for i := levelOfDetail; i > 0; i-- {
-		digit := 0
 		mask := 1 << (i - 1)
+		digit := byte('0')
 		if (tileX & mask) != 0 {
 			digit++
 		}
 		if (tileY & mask) != 0 {
-			digit++
-			digit++
+			digit += 2
 		}
-		buffer.WriteString(strconv.Itoa(digit))
+		dst = append(dst, digit)
+	}
+	return dst
+}
+
+/// <summary>
+/// Converts tile XY coordinates into a QuadKey at a specified level of detail.
+/// The QuadKey always has exactly levelOfDetail digits, leading zeros
+/// included, so for every valid QuadKey q the round trip
+/// TileXYToQuadKey(QuadKeyToTileXY(q)) returns q and string equality is
+/// tile identity.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>A string containing the QuadKey.</returns>
+func TileXYToQuadKey(tileX int, tileY int, levelOfDetail uint) string {
+	return string(AppendQuadKey(make([]byte, 0, levelOfDetail), tileX, tileY, levelOfDetail))
+}
+
+/// <summary>
+/// Converts tile XY coordinates into a QuadKey at a specified level of
+/// detail, rejecting levels and coordinates outside the tile system.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>A string containing the QuadKey, and an error if the level is
+/// above MaxLevel or a coordinate is outside the map.</returns>
+func TileXYToQuadKeyErr(tileX int, tileY int, levelOfDetail uint) (string, error) {
+	if err := validLevel(levelOfDetail); err != nil {
+		return "", err
+	}
+	tileCount := tilesPerSide(levelOfDetail)
+	if tileX < 0 || tileX >= tileCount || tileY < 0 || tileY >= tileCount {
+		return "", fmt.Errorf("tile (%d, %d) outside the %dx%d tiles of level %d", tileX, tileY, tileCount, tileCount, levelOfDetail)
 	}
-	return buffer.String()
+	return TileXYToQuadKey(tileX, tileY, levelOfDetail), nil
 }
 
 /// <summary>
-/// Converts a QuadKey into tile XY coordinates.
+/// Converts a QuadKey into tile XY coordinates. An invalid QuadKey yields
+/// -1 for both coordinates; use QuadKeyToTileXYErr to tell why. A QuadKey
+/// longer than MaxLevel digits is invalid too and also yields -1, where
+/// earlier versions decoded it into coordinates beyond any supported map
+/// (overflowing int past 62 digits). The returned level is always the
+/// QuadKey's length.
 /// </summary>
 /// <param name="quadKey">QuadKey of the tile.</param>
 /// <param name="tileX">Output parameter receiving the tile X coordinate.</param>
 /// <param name="tileY">Output parameter receiving the tile Y coordinate.</param>
 /// <param name="levelOfDetail">Output parameter receiving the level of detail.</param>
 func QuadKeyToTileXY(quadKey string) (tileX int, tileY int, levelOfDetail uint) {
-	tileX = 0
-	tileY = 0
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return -1, -1, uint(len(quadKey))
+	}
+	return
+}
+
+/// <summary>
+/// Converts a QuadKey into tile XY coordinates, reporting an invalid
+/// QuadKey as an error rather than through -1 coordinates. The empty
+/// QuadKey is the level 0 tile covering the whole map.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="tileX">Output parameter receiving the tile X coordinate.</param>
+/// <param name="tileY">Output parameter receiving the tile Y coordinate.</param>
+/// <param name="levelOfDetail">Output parameter receiving the level of detail.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is
+/// longer than MaxLevel or has a digit other than 0, 1, 2 or 3.</param>
+func QuadKeyToTileXYErr(quadKey string) (tileX int, tileY int, levelOfDetail uint, err error) {
+	if len(quadKey) > MaxLevel {
+		return 0, 0, 0, fmt.Errorf("quadkey has %d digits, more than the maximum level %d", len(quadKey), MaxLevel)
+	}
 	levelOfDetail = uint(len(quadKey))
 	for i := levelOfDetail; i > 0; i-- {
 		mask := 1 << (i - 1)
-		switch string(quadKey[levelOfDetail-i]) {
-		case "0":
+		switch quadKey[levelOfDetail-i] {
+		case '0':
 
-		case "1":
+		case '1':
 			tileX |= mask
 
-		case "2":
+		case '2':
 			tileY |= mask
 
-		case "3":
+		case '3':
 			tileX |= mask
 			tileY |= mask
 
 		default:
-			tileX = -1
-			tileY = -1
+			index := levelOfDetail - i
+			return 0, 0, 0, fmt.Errorf("invalid quadkey digit %q at index %d", quadKey[index], index)
 		}
 	}
 	return
 }
 
+/// <summary>
+/// A QuadKey identifying a tile: one base-4 digit per level of detail.
+/// </summary>
+type QuadKey string
+
+/// <summary>
+/// Determines whether the QuadKey is well formed: at most MaxLevel digits,
+/// each one of 0, 1, 2 or 3. Check this before converting untrusted input,
+/// since QuadKeyToTileXY reports bad digits only through -1 coordinates.
+/// </summary>
+/// <returns>True if the QuadKey is valid.</returns>
+func (q QuadKey) Valid() bool {
+	if len(q) > MaxLevel {
+		return false
+	}
+	for i := 0; i < len(q); i++ {
+		if q[i] < '0' || q[i] > '3' {
+			return false
+		}
+	}
+	return true
+}
+
+/// <summary>
+/// Determines the level of detail of the QuadKey, its number of digits.
+/// </summary>
+/// <returns>The level of detail.</returns>
+func (q QuadKey) Level() uint {
+	return uint(len(q))
+}
+
+/// <summary>
+/// Converts the QuadKey into a string.
+/// </summary>
+/// <returns>The QuadKey's digits.</returns>
+func (q QuadKey) String() string {
+	return string(q)
+}
+
+/// <summary>
+/// Encodes the QuadKey as text, implementing encoding.TextMarshaler.
+/// </summary>
+/// <returns>The QuadKey's digits, and an error if the QuadKey is invalid.</returns>
+func (q QuadKey) MarshalText() ([]byte, error) {
+	if _, _, _, err := QuadKeyToTileXYErr(string(q)); err != nil {
+		return nil, err
+	}
+	return []byte(q), nil
+}
+
+/// <summary>
+/// Decodes the QuadKey from text, implementing encoding.TextUnmarshaler.
+/// </summary>
+/// <param name="text">The QuadKey's digits.</param>
+/// <returns>An error if the text is not a valid QuadKey.</returns>
+func (q *QuadKey) UnmarshalText(text []byte) error {
+	if _, _, _, err := QuadKeyToTileXYErr(string(text)); err != nil {
+		return err
+	}
+	*q = QuadKey(text)
+	return nil
+}
+
+/// <summary>
+/// Encodes the QuadKey as a JSON string, implementing json.Marshaler.
+/// </summary>
+/// <returns>The JSON encoding, and an error if the QuadKey is invalid.</returns>
+func (q QuadKey) MarshalJSON() ([]byte, error) {
+	if _, err := q.MarshalText(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(q))
+}
+
+/// <summary>
+/// Decodes the QuadKey from a JSON string, implementing json.Unmarshaler.
+/// </summary>
+/// <param name="data">The JSON encoding.</param>
+/// <returns>An error if the data is not a JSON string holding a valid QuadKey.</returns>
+func (q *QuadKey) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return q.UnmarshalText([]byte(text))
+}
+
+/// <summary>
+/// Determines whether a string is a well-formed QuadKey.
+/// </summary>
+/// <param name="quadKey">The string to check.</param>
+/// <returns>True if the string is a QuadKey.</returns>
+func validQuadKey(quadKey string) bool {
+	return QuadKey(quadKey).Valid()
+}
+
+/// <summary>
+/// Converts a QuadKey into its tile's Z-order (Morton) index, which is the
+/// QuadKey read as a base-4 number.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The Z-order index.</returns>
+func quadKeyToZIndex(quadKey string) uint64 {
+	var index uint64
+	for i := 0; i < len(quadKey); i++ {
+		index = index<<2 | uint64(quadKey[i]-'0')
+	}
+	return index
+}
+
+/// <summary>
+/// Converts a Z-order (Morton) index into the QuadKey of the tile at a
+/// specified level of detail.
+/// </summary>
+/// <param name="index">Z-order index of the tile.</param>
+/// <param name="levelOfDetail">Level of detail of the tile.</param>
+/// <returns>A string containing the QuadKey.</returns>
+func zIndexToQuadKey(index uint64, levelOfDetail uint) string {
+	digits := make([]byte, levelOfDetail)
+	for i := int(levelOfDetail) - 1; i >= 0; i-- {
+		digits[i] = byte('0' + index&3)
+		index >>= 2
+	}
+	return string(digits)
+}
+
+/// <summary>
+/// Converts a QuadKey into a compact Morton (Z-order) code for storage as
+/// an integer. The code is the QuadKey read as a base-4 number, so it
+/// alone cannot tell "3" from "03" or "003": the level, the QuadKey's
+/// length, is returned separately and must be stored alongside the code.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="code">Output parameter receiving the Morton code.</param>
+/// <param name="level">Output parameter receiving the level of detail.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func QuadKeyToMorton(quadKey string) (code uint64, level uint, err error) {
+	if _, _, _, err = QuadKeyToTileXYErr(quadKey); err != nil {
+		return 0, 0, err
+	}
+	return quadKeyToZIndex(quadKey), uint(len(quadKey)), nil
+}
+
+/// <summary>
+/// Converts a Morton (Z-order) code and its level of detail back into a
+/// QuadKey, restoring any leading zeros from the level.
+/// </summary>
+/// <param name="code">Morton code of the tile.</param>
+/// <param name="level">Level of detail of the tile, from 0 to 23.</param>
+/// <returns>A string containing the QuadKey, and an error if the level is
+/// above MaxLevel or the code has more than 2 * level bits.</returns>
+func MortonToQuadKey(code uint64, level uint) (string, error) {
+	if err := validLevel(level); err != nil {
+		return "", err
+	}
+	if code>>(2*level) != 0 {
+		return "", fmt.Errorf("morton code %d too large for level %d", code, level)
+	}
+	return zIndexToQuadKey(code, level), nil
+}
+
+/// <summary>
+/// Spreads the low 32 bits of a value into the even bits of a 64-bit word.
+/// </summary>
+/// <param name="v">The value to spread.</param>
+/// <returns>The spread bits.</returns>
+func spreadBits(v uint64) uint64 {
+	v &= 0x00000000FFFFFFFF
+	v = (v | v<<16) & 0x0000FFFF0000FFFF
+	v = (v | v<<8) & 0x00FF00FF00FF00FF
+	v = (v | v<<4) & 0x0F0F0F0F0F0F0F0F
+	v = (v | v<<2) & 0x3333333333333333
+	v = (v | v<<1) & 0x5555555555555555
+	return v
+}
+
+/// <summary>
+/// Gathers the even bits of a 64-bit word into the low 32 bits; the
+/// inverse of spreadBits.
+/// </summary>
+/// <param name="v">The word to gather from.</param>
+/// <returns>The gathered bits.</returns>
+func gatherBits(v uint64) uint64 {
+	v &= 0x5555555555555555
+	v = (v | v>>1) & 0x3333333333333333
+	v = (v | v>>2) & 0x0F0F0F0F0F0F0F0F
+	v = (v | v>>4) & 0x00FF00FF00FF00FF
+	v = (v | v>>8) & 0x0000FFFF0000FFFF
+	v = (v | v>>16) & 0x00000000FFFFFFFF
+	return v
+}
+
+/// <summary>
+/// Converts tile XY coordinates into a Morton (Z-order) code by
+/// interleaving their bits, X in the even bits and Y in the odd bits. This
+/// matches QuadKeyToMorton for the tile's QuadKey at any level.
+/// </summary>
+/// <param name="tileX">Tile X coordinate, non-negative.</param>
+/// <param name="tileY">Tile Y coordinate, non-negative.</param>
+/// <returns>The Morton code.</returns>
+func TileXYToMorton(tileX int, tileY int) uint64 {
+	return spreadBits(uint64(tileX)) | spreadBits(uint64(tileY))<<1
+}
+
+/// <summary>
+/// Converts a Morton (Z-order) code into tile XY coordinates by
+/// de-interleaving its bits; the inverse of TileXYToMorton.
+/// </summary>
+/// <param name="code">Morton code of the tile.</param>
+/// <param name="tileX">Output parameter receiving the tile X coordinate.</param>
+/// <param name="tileY">Output parameter receiving the tile Y coordinate.</param>
+func MortonToTileXY(code uint64) (tileX int, tileY int) {
+	return int(gatherBits(code)), int(gatherBits(code >> 1))
+}
+
 func LatLongToQuadKey(latitude float64, longitude float64, levelOfDetail uint) string {
 	x, y := LatLongToPixelXY(latitude, longitude, levelOfDetail)
 	tileX, tileY := PixelXYToTileXY(x, y)
 	return TileXYToQuadKey(tileX, tileY, levelOfDetail)
 }
+
+/// <summary>
+/// Converts a batch of points into QuadKeys at a fixed level of detail,
+/// written back to back into one byte slice: since every key has
+/// levelOfDetail digits, the key of point i is
+/// keys[i*levelOfDetail : (i+1)*levelOfDetail]. The slice is the only
+/// allocation, however many points there are.
+/// </summary>
+/// <param name="lats">Latitudes of the points, in degrees.</param>
+/// <param name="longs">Longitudes of the points, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>The concatenated QuadKeys, and an error if the level is above
+/// MaxLevel or lats and longs differ in length.</returns>
+func LatLongsToQuadKeyBytes(lats []float64, longs []float64, levelOfDetail uint) ([]byte, error) {
+	if err := validLevel(levelOfDetail); err != nil {
+		return nil, err
+	}
+	if len(lats) != len(longs) {
+		return nil, fmt.Errorf("%d latitudes but %d longitudes", len(lats), len(longs))
+	}
+	keys := make([]byte, 0, len(lats)*int(levelOfDetail))
+	for i := range lats {
+		pixelX, pixelY := LatLongToPixelXY(lats[i], longs[i], levelOfDetail)
+		tileX, tileY := PixelXYToTileXY(pixelX, pixelY)
+		keys = AppendQuadKey(keys, tileX, tileY, levelOfDetail)
+	}
+	return keys, nil
+}
+
+/// <summary>
+/// Converts a batch of points into QuadKeys at a fixed level of detail.
+/// The keys are built in one scratch buffer by LatLongsToQuadKeyBytes and
+/// copied into a single string that every returned key slices, so the
+/// batch costs a constant number of allocations rather than one per point.
+/// </summary>
+/// <param name="lats">Latitudes of the points, in degrees.</param>
+/// <param name="longs">Longitudes of the points, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys in input order, and an error if the level is above
+/// MaxLevel or lats and longs differ in length.</returns>
+func LatLongsToQuadKeys(lats []float64, longs []float64, levelOfDetail uint) ([]string, error) {
+	buffer, err := LatLongsToQuadKeyBytes(lats, longs, levelOfDetail)
+	if err != nil {
+		return nil, err
+	}
+	all := string(buffer)
+	keys := make([]string, len(lats))
+	for i := range keys {
+		keys[i] = all[i*int(levelOfDetail) : (i+1)*int(levelOfDetail)]
+	}
+	return keys, nil
+}
+
+/// <summary>
+/// Converts pixel XY coordinates in a local pixel space, whose origin sits
+/// at global pixel (originX, originY), into the QuadKey of the containing
+/// tile. Shifting the origin by whole tiles shifts tile numbering by the
+/// same amount; a local pixel p belongs to the tile containing global pixel
+/// p + origin.
+/// </summary>
+/// <param name="pixelX">Local pixel X coordinate.</param>
+/// <param name="pixelY">Local pixel Y coordinate.</param>
+/// <param name="originX">Global pixel X coordinate of the local origin.</param>
+/// <param name="originY">Global pixel Y coordinate of the local origin.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKey, and false if the pixel falls outside the map.</returns>
+func PixelXYToQuadKeyWithOrigin(pixelX int, pixelY int, originX int, originY int, levelOfDetail uint) (string, bool) {
+	globalX, globalY := pixelX+originX, pixelY+originY
+	mapSize := int(MapSize(levelOfDetail))
+	if globalX < 0 || globalY < 0 || globalX >= mapSize || globalY >= mapSize {
+		return "", false
+	}
+	tileX, tileY := PixelXYToTileXY(globalX, globalY)
+	return TileXYToQuadKey(tileX, tileY, levelOfDetail), true
+}
+
+/// <summary>
+/// Converts a QuadKey into the local pixel XY coordinates of the upper-left
+/// pixel of the tile, in a pixel space whose origin sits at global pixel
+/// (originX, originY). This is the inverse of PixelXYToQuadKeyWithOrigin.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="originX">Global pixel X coordinate of the local origin.</param>
+/// <param name="originY">Global pixel Y coordinate of the local origin.</param>
+/// <param name="pixelX">Output parameter receiving the local pixel X coordinate.</param>
+/// <param name="pixelY">Output parameter receiving the local pixel Y coordinate.</param>
+/// <param name="ok">Output parameter receiving false if the QuadKey is invalid.</param>
+func QuadKeyToPixelXYWithOrigin(quadKey string, originX int, originY int) (pixelX int, pixelY int, ok bool) {
+	if !validQuadKey(quadKey) {
+		return 0, 0, false
+	}
+	tileX, tileY, _ := QuadKeyToTileXY(quadKey)
+	pixelX, pixelY = TileXYToPixelXY(tileX, tileY)
+	return pixelX - originX, pixelY - originY, true
+}
+
+/// <summary>
+/// One step of a FacetPath: the tile containing a point at one level.
+/// </summary>
+type FacetLevel struct {
+	Level   uint
+	QuadKey string
+}
+
+/// <summary>
+/// Determines the tiles containing a point at several levels of detail,
+/// labeled with their levels, for hierarchical drill-down (faceted) search
+/// keyed by tiles. All keys are prefixes of the key at the deepest level,
+/// so each step is guaranteed to lie inside the previous one.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levels">Levels of detail in strictly ascending order, each
+/// at most MaxLevel.</param>
+/// <returns>One entry per level, or nil if the levels are not strictly
+/// ascending or exceed MaxLevel.</returns>
+func FacetPath(latitude float64, longitude float64, levels []uint) []FacetLevel {
+	if len(levels) == 0 {
+		return nil
+	}
+	for i, level := range levels {
+		if level > MaxLevel || (i > 0 && level <= levels[i-1]) {
+			return nil
+		}
+	}
+	deepest := LatLongToQuadKey(latitude, longitude, levels[len(levels)-1])
+	path := make([]FacetLevel, len(levels))
+	for i, level := range levels {
+		path[i] = FacetLevel{Level: level, QuadKey: deepest[:level]}
+	}
+	return path
+}
@@ -0,0 +1,237 @@
+// Quadkeys project Quadkeys_test.go
+package Quadkeys
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestQuadKeyToTileXYInvalid(t *testing.T) {
+	for _, quadKey := range []string{"04", "3a1", strings.Repeat("0", MaxLevel+1), strings.Repeat("3", 70)} {
+		tileX, tileY, level := QuadKeyToTileXY(quadKey)
+		if tileX != -1 || tileY != -1 || level != uint(len(quadKey)) {
+			t.Errorf("QuadKeyToTileXY(%q) = %d, %d, %d, want -1, -1, %d", quadKey, tileX, tileY, level, len(quadKey))
+		}
+	}
+	tileX, tileY, level := QuadKeyToTileXY(strings.Repeat("3", MaxLevel))
+	if want := 1<<MaxLevel - 1; tileX != want || tileY != want || level != MaxLevel {
+		t.Errorf("QuadKeyToTileXY at MaxLevel = %d, %d, %d", tileX, tileY, level)
+	}
+}
+
+func TestTileXYMortonRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		level := uint(r.Intn(MaxLevel + 1))
+		n := tilesPerSide(level)
+		tileX, tileY := r.Intn(n), r.Intn(n)
+		if i < 4 {
+			// The extreme corners of the full 23-bit range.
+			level, tileX, tileY = MaxLevel, (i&1)*(1<<MaxLevel-1), (i>>1)*(1<<MaxLevel-1)
+		}
+		code := TileXYToMorton(tileX, tileY)
+		if x, y := MortonToTileXY(code); x != tileX || y != tileY {
+			t.Fatalf("MortonToTileXY(TileXYToMorton(%d, %d)) = %d, %d", tileX, tileY, x, y)
+		}
+		quadKey := TileXYToQuadKey(tileX, tileY, level)
+		want, wantLevel, err := QuadKeyToMorton(quadKey)
+		if err != nil || code != want || wantLevel != level {
+			t.Fatalf("TileXYToMorton(%d, %d) = %d, QuadKeyToMorton(%q) = %d, %d, %v", tileX, tileY, code, quadKey, want, wantLevel, err)
+		}
+		if back, err := MortonToQuadKey(code, level); err != nil || back != quadKey {
+			t.Fatalf("MortonToQuadKey(%d, %d) = %q, %v, want %q", code, level, back, err, quadKey)
+		}
+	}
+}
+
+func TestTileXYToMortonKnownValues(t *testing.T) {
+	tests := []struct {
+		tileX, tileY int
+		code         uint64
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{0, 1, 2},
+		{1, 1, 3},
+		{3, 5, 0x27},
+		{1<<MaxLevel - 1, 1<<MaxLevel - 1, 1<<(2*MaxLevel) - 1},
+	}
+	for _, test := range tests {
+		if code := TileXYToMorton(test.tileX, test.tileY); code != test.code {
+			t.Errorf("TileXYToMorton(%d, %d) = %#x, want %#x", test.tileX, test.tileY, code, test.code)
+		}
+	}
+}
+
+func benchmarkPoints(count int) (lats []float64, longs []float64) {
+	r := rand.New(rand.NewSource(1))
+	lats, longs = make([]float64, count), make([]float64, count)
+	for i := range lats {
+		lats[i] = r.Float64()*2*MaxLatitude - MaxLatitude
+		longs[i] = r.Float64()*360 - 180
+	}
+	return
+}
+
+func TestLatLongsToQuadKeysAllocations(t *testing.T) {
+	lats, longs := benchmarkPoints(1000)
+	keys, err := LatLongsToQuadKeys(lats, longs, 17)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range keys {
+		if want := LatLongToQuadKey(lats[i], longs[i], 17); keys[i] != want {
+			t.Fatalf("key %d = %q, want %q", i, keys[i], want)
+		}
+	}
+	if allocs := testing.AllocsPerRun(10, func() { LatLongsToQuadKeys(lats, longs, 17) }); allocs > 3 {
+		t.Errorf("LatLongsToQuadKeys made %v allocations for 1000 points, want at most 3", allocs)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { LatLongsToQuadKeyBytes(lats, longs, 17) }); allocs > 1 {
+		t.Errorf("LatLongsToQuadKeyBytes made %v allocations for 1000 points, want at most 1", allocs)
+	}
+}
+
+func BenchmarkLatLongToQuadKeyLoop(b *testing.B) {
+	lats, longs := benchmarkPoints(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := range lats {
+			LatLongToQuadKey(lats[j], longs[j], 17)
+		}
+	}
+}
+
+func BenchmarkLatLongsToQuadKeys(b *testing.B) {
+	lats, longs := benchmarkPoints(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LatLongsToQuadKeys(lats, longs, 17)
+	}
+}
+
+func BenchmarkLatLongsToQuadKeyBytes(b *testing.B) {
+	lats, longs := benchmarkPoints(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LatLongsToQuadKeyBytes(lats, longs, 17)
+	}
+}
+
+// tileXYToQuadKeyItoa is the former TileXYToQuadKey, formatting each digit
+// with strconv.Itoa into a bytes.Buffer; kept to benchmark against.
+func tileXYToQuadKeyItoa(tileX int, tileY int, levelOfDetail uint) string {
+	var quadKey bytes.Buffer
+	for i := levelOfDetail; i > 0; i-- {
+		digit := 0
+		mask := 1 << (i - 1)
+		if (tileX & mask) != 0 {
+			digit++
+		}
+		if (tileY & mask) != 0 {
+			digit += 2
+		}
+		quadKey.WriteString(strconv.Itoa(digit))
+	}
+	return quadKey.String()
+}
+
+func TestTileXYToQuadKeyMatchesItoa(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		level := uint(r.Intn(MaxLevel + 1))
+		n := tilesPerSide(level)
+		tileX, tileY := r.Intn(n), r.Intn(n)
+		if got, want := TileXYToQuadKey(tileX, tileY, level), tileXYToQuadKeyItoa(tileX, tileY, level); got != want {
+			t.Fatalf("TileXYToQuadKey(%d, %d, %d) = %q, want %q", tileX, tileY, level, got, want)
+		}
+	}
+}
+
+// quadKeySink keeps benchmarked results alive so they are not optimized away.
+var quadKeySink string
+
+func BenchmarkTileXYToQuadKey(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		quadKeySink = TileXYToQuadKey(i&(1<<MaxLevel-1), (i>>3)&(1<<MaxLevel-1), MaxLevel)
+	}
+}
+
+func BenchmarkTileXYToQuadKeyItoa(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		quadKeySink = tileXYToQuadKeyItoa(i&(1<<MaxLevel-1), (i>>3)&(1<<MaxLevel-1), MaxLevel)
+	}
+}
+
+func FuzzLatLongToQuadKey(f *testing.F) {
+	f.Add(0.0, 0.0, uint8(1))
+	f.Add(47.6062, -122.3321, uint8(17))
+	f.Add(MaxLatitude, 180.0, uint8(MaxLevel))
+	f.Add(MinLatitude, -180.0, uint8(MaxLevel))
+	f.Add(-33.8688, 151.2093, uint8(23))
+	f.Fuzz(func(t *testing.T, latitude float64, longitude float64, level uint8) {
+		if !(latitude >= MinLatitude && latitude <= MaxLatitude && longitude >= -180 && longitude <= 180) {
+			t.Skip()
+		}
+		levelOfDetail := uint(level)%MaxLevel + 1
+		quadKey := LatLongToQuadKey(latitude, longitude, levelOfDetail)
+		minLat, minLong, maxLat, maxLong, err := QuadKeyToBoundingBox(quadKey)
+		if err != nil {
+			t.Fatalf("QuadKeyToBoundingBox(%q): %v", quadKey, err)
+		}
+		// Allow only for rounding in the degree conversions, far less than
+		// a pixel even at MaxLevel.
+		const epsilon = 1e-9
+		if latitude < minLat-epsilon || latitude > maxLat+epsilon || longitude < minLong-epsilon || longitude > maxLong+epsilon {
+			t.Errorf("(%v, %v) at level %d is in %q, whose bounds are [%v, %v] x [%v, %v]", latitude, longitude, levelOfDetail, quadKey, minLat, maxLat, minLong, maxLong)
+		}
+	})
+}
+
+func FuzzQuadKeyRoundTrip(f *testing.F) {
+	for _, quadKey := range []string{"", "0", "3", "003", "0123", "30000000000000000000000", strings.Repeat("3", MaxLevel), "4", "01x"} {
+		f.Add(quadKey)
+	}
+	f.Fuzz(func(t *testing.T, quadKey string) {
+		tileX, tileY, level, err := QuadKeyToTileXYErr(quadKey)
+		if err != nil {
+			if QuadKey(quadKey).Valid() {
+				t.Fatalf("QuadKeyToTileXYErr rejected valid QuadKey %q: %v", quadKey, err)
+			}
+			t.Skip()
+		}
+		if got := TileXYToQuadKey(tileX, tileY, level); got != quadKey {
+			t.Errorf("TileXYToQuadKey(QuadKeyToTileXY(%q)) = %q", quadKey, got)
+		}
+		if x, y, l := QuadKeyToTileXY(quadKey); x != tileX || y != tileY || l != level {
+			t.Errorf("QuadKeyToTileXY(%q) = %d, %d, %d, want %d, %d, %d", quadKey, x, y, l, tileX, tileY, level)
+		}
+	})
+}
+
+func TestLevelForResolution(t *testing.T) {
+	for _, latitude := range []float64{0, 47.6, -60, MaxLatitude} {
+		for level := uint(1); level <= MaxLevel; level++ {
+			if got := LevelForResolution(GroundResolution(latitude, level), latitude); got != level {
+				t.Errorf("LevelForResolution(GroundResolution(%v, %d)) = %d", latitude, level, got)
+			}
+		}
+	}
+	if got := LevelForResolution(1e9, 0); got != 1 {
+		t.Errorf("LevelForResolution of a coarse resolution = %d, want 1", got)
+	}
+	if got := LevelForResolution(1e-9, 0); got != MaxLevel {
+		t.Errorf("LevelForResolution of a fine resolution = %d, want %d", got, MaxLevel)
+	}
+	for _, c := range [][2]float64{{-1, 0}, {0, 0}, {math.Inf(-1), 0}, {math.NaN(), 0}, {10, math.NaN()}, {math.Inf(1), 0}} {
+		if got := LevelForResolution(c[0], c[1]); got != 1 {
+			t.Errorf("LevelForResolution(%v, %v) = %d, want 1", c[0], c[1], got)
+		}
+	}
+}
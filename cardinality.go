@@ -0,0 +1,76 @@
+// Quadkeys project cardinality.go
+package Quadkeys
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// Number of index bits in the HyperLogLog sketch; 2^14 one-byte registers.
+const hyperLogLogPrecision = 14
+
+/// <summary>
+/// Hashes a QuadKey to 64 well-mixed bits: FNV-1a followed by the
+/// SplitMix64 finalizer, since FNV alone spreads similar short keys poorly
+/// across the high bits.
+/// </summary>
+/// <param name="quadKey">The QuadKey to hash.</param>
+/// <returns>The hash.</returns>
+func quadKeyHash(quadKey string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(quadKey))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+/// <summary>
+/// Estimates the number of distinct tiles a set of points occupies at a
+/// level of detail with a HyperLogLog sketch, using a fixed 16 KiB of
+/// memory however many points there are. Each point's QuadKey is hashed
+/// into the sketch. The estimate has a standard error of about 0.8%
+/// (1.04 / sqrt(2^14)); small counts use linear counting and are
+/// close to exact.
+/// </summary>
+/// <param name="lats">Latitudes of the points, in degrees.</param>
+/// <param name="lons">Longitudes of the points, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The estimated number of distinct tiles, or 0 if lats and lons
+/// differ in length.</returns>
+func ApproxDistinctTiles(lats []float64, lons []float64, levelOfDetail uint) uint64 {
+	if len(lats) != len(lons) || len(lats) == 0 {
+		return 0
+	}
+
+	const m = 1 << hyperLogLogPrecision
+	registers := make([]uint8, m)
+	for i := range lats {
+		hash := quadKeyHash(LatLongToQuadKey(lats[i], lons[i], levelOfDetail))
+		index := hash >> (64 - hyperLogLogPrecision)
+		rank := uint8(bits.LeadingZeros64(hash<<hyperLogLogPrecision|1<<(hyperLogLogPrecision-1)) + 1)
+		if rank > registers[index] {
+			registers[index] = rank
+		}
+	}
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/float64(m))
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(float64(m)/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
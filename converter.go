@@ -0,0 +1,66 @@
+// Quadkeys project converter.go
+package Quadkeys
+
+import (
+	"io"
+	"sync"
+)
+
+/// <summary>
+/// Writes QuadKeys straight to an io.Writer, such as an HTTP response,
+/// using pooled scratch buffers, so heavy concurrent use allocates nothing
+/// per call: no string is built for a QuadKey that is only to be written.
+/// Callers that need the QuadKey in memory should use AppendQuadKey with a
+/// buffer of their own. A Converter is safe for concurrent use by multiple
+/// goroutines; its zero value is ready to use, but it must not be copied
+/// after first use.
+/// </summary>
+type Converter struct {
+	buffers sync.Pool
+}
+
+/// <summary>
+/// Creates a converter.
+/// </summary>
+/// <returns>The converter.</returns>
+func NewConverter() *Converter {
+	return &Converter{}
+}
+
+/// <summary>
+/// Writes the QuadKey of a tile at a specified level of detail, as
+/// TileXYToQuadKey would return it.
+/// </summary>
+/// <param name="w">The writer to write the QuadKey to.</param>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The number of bytes written, and any error from w.</returns>
+func (c *Converter) WriteTileXYQuadKey(w io.Writer, tileX int, tileY int, levelOfDetail uint) (int, error) {
+	buffer, _ := c.buffers.Get().(*[]byte)
+	if buffer == nil {
+		buffer = new([]byte)
+	}
+	*buffer = AppendQuadKey((*buffer)[:0], tileX, tileY, levelOfDetail)
+	n, err := w.Write(*buffer)
+	c.buffers.Put(buffer)
+	return n, err
+}
+
+/// <summary>
+/// Writes the QuadKey of the tile containing a point from
+/// latitude/longitude WGS-84 coordinates (in degrees) at a specified level
+/// of detail, as LatLongToQuadKey would return it.
+/// </summary>
+/// <param name="w">The writer to write the QuadKey to.</param>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The number of bytes written, and any error from w.</returns>
+func (c *Converter) WriteLatLongQuadKey(w io.Writer, latitude float64, longitude float64, levelOfDetail uint) (int, error) {
+	pixelX, pixelY := LatLongToPixelXY(latitude, longitude, levelOfDetail)
+	tileX, tileY := PixelXYToTileXY(pixelX, pixelY)
+	return c.WriteTileXYQuadKey(w, tileX, tileY, levelOfDetail)
+}
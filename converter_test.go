@@ -0,0 +1,71 @@
+// Quadkeys project converter_test.go
+package Quadkeys
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestConverterWritesQuadKeys(t *testing.T) {
+	var converter Converter
+	points := [][2]float64{{47.6062, -122.3321}, {0, 0}, {-33.8688, 151.2093}, {MaxLatitude, 180}}
+	var wait sync.WaitGroup
+	for _, point := range points {
+		wait.Add(1)
+		go func(latitude float64, longitude float64) {
+			defer wait.Done()
+			for level := uint(0); level <= MaxLevel; level++ {
+				var out bytes.Buffer
+				n, err := converter.WriteLatLongQuadKey(&out, latitude, longitude, level)
+				want := LatLongToQuadKey(latitude, longitude, level)
+				if err != nil || n != len(want) || out.String() != want {
+					t.Errorf("WriteLatLongQuadKey(%v, %v, %d) wrote %q, %d, %v, want %q", latitude, longitude, level, out.String(), n, err, want)
+				}
+			}
+		}(point[0], point[1])
+	}
+	wait.Wait()
+}
+
+func TestAppendQuadKeyAllocations(t *testing.T) {
+	buffer := make([]byte, 0, MaxLevel)
+	if got := string(AppendQuadKey(buffer, 3, 5, 3)); got != TileXYToQuadKey(3, 5, 3) {
+		t.Errorf("AppendQuadKey(3, 5, 3) = %q", got)
+	}
+	if got := string(AppendQuadKey([]byte("x/"), 1, 0, 1)); got != "x/1" {
+		t.Errorf("AppendQuadKey kept %q, want %q", got, "x/1")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		buffer = AppendQuadKey(buffer[:0], 1234567, 7654321, MaxLevel)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendQuadKey into a large enough buffer made %v allocations", allocs)
+	}
+	converter := NewConverter()
+	allocs = testing.AllocsPerRun(100, func() {
+		converter.WriteLatLongQuadKey(io.Discard, 47.6062, -122.3321, MaxLevel)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteLatLongQuadKey made %v allocations", allocs)
+	}
+}
+
+func BenchmarkAppendQuadKey(b *testing.B) {
+	buffer := make([]byte, 0, MaxLevel)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buffer = AppendQuadKey(buffer[:0], i&(1<<MaxLevel-1), (i>>3)&(1<<MaxLevel-1), MaxLevel)
+	}
+}
+
+func BenchmarkConverterWriteLatLongQuadKey(b *testing.B) {
+	converter := NewConverter()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			converter.WriteLatLongQuadKey(io.Discard, 47.6062, -122.3321, MaxLevel)
+		}
+	})
+}
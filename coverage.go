@@ -0,0 +1,782 @@
+// Quadkeys project coverage.go
+package Quadkeys
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+/// <summary>
+/// The most tiles CoverBoundingBox will return. Coverings that could
+/// otherwise grow without bound, such as TilesForFeatures and VisibleTiles,
+/// stop at the same limit.
+/// </summary>
+const MaxCoverTiles = 1 << 20
+
+/// <summary>
+/// Determines the inclusive tile XY ranges covering a bounding box. A box
+/// whose minimum longitude is greater than its maximum longitude crosses
+/// the antimeridian and yields two ranges, one on each side, unless they
+/// meet, in which case a single full-width range is returned.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The ranges as {minX, minY, maxX, maxY}, or nil if the box's
+/// minimum latitude is greater than its maximum latitude.</returns>
+func boxTileRanges(box [4]float64, levelOfDetail uint) [][4]int {
+	minLat, minLong, maxLat, maxLong := box[0], box[1], box[2], box[3]
+	if minLat > maxLat {
+		return nil
+	}
+
+	tileRange := func(west float64, east float64) [4]int {
+		pixelX1, pixelY1 := LatLongToPixelXY(maxLat, west, levelOfDetail)
+		pixelX2, pixelY2 := LatLongToPixelXY(minLat, east, levelOfDetail)
+		minX, minY := PixelXYToTileXY(pixelX1, pixelY1)
+		maxX, maxY := PixelXYToTileXY(pixelX2, pixelY2)
+		return [4]int{minX, minY, maxX, maxY}
+	}
+
+	if minLong <= maxLong {
+		return [][4]int{tileRange(minLong, maxLong)}
+	}
+	east := tileRange(minLong, MaxLongitude)
+	west := tileRange(MinLongitude, maxLong)
+	if west[2] >= east[0] {
+		return [][4]int{{0, east[1], tilesPerSide(levelOfDetail) - 1, east[3]}}
+	}
+	return [][4]int{west, east}
+}
+
+/// <summary>
+/// Determines whether a tile lies within any of a set of tile ranges.
+/// </summary>
+/// <param name="ranges">Inclusive ranges as {minX, minY, maxX, maxY}.</param>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <returns>True if the tile is in a range.</returns>
+func inTileRanges(ranges [][4]int, tileX int, tileY int) bool {
+	for _, r := range ranges {
+		if tileX >= r[0] && tileX <= r[2] && tileY >= r[1] && tileY <= r[3] {
+			return true
+		}
+	}
+	return false
+}
+
+/// <summary>
+/// Determines how many tiles cover a bounding box at a specified level of
+/// detail, without materializing them.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The number of covering tiles.</returns>
+func CountTilesForBoundingBox(box [4]float64, levelOfDetail uint) uint64 {
+	var count uint64
+	for _, r := range boxTileRanges(box, levelOfDetail) {
+		count += uint64(r[2]-r[0]+1) * uint64(r[3]-r[1]+1)
+	}
+	return count
+}
+
+/// <summary>
+/// Visits the tiles of a set of ranges sharing the same rows, row by row
+/// from north to south and west to east within a row, stopping at the
+/// first error.
+/// </summary>
+/// <param name="ranges">Inclusive ranges as {minX, minY, maxX, maxY}, west to east.</param>
+/// <param name="levelOfDetail">Level of detail of the tiles.</param>
+/// <param name="visit">Function called with each tile's QuadKey.</param>
+/// <returns>The error returned by visit, if any.</returns>
+func visitTileRanges(ranges [][4]int, levelOfDetail uint, visit func(quadKey string) error) error {
+	if len(ranges) == 0 {
+		return nil
+	}
+	for tileY := ranges[0][1]; tileY <= ranges[0][3]; tileY++ {
+		for _, r := range ranges {
+			for tileX := r[0]; tileX <= r[2]; tileX++ {
+				if err := visit(TileXYToQuadKey(tileX, tileY, levelOfDetail)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/// <summary>
+/// Determines the tiles covering a bounding box at a specified level of
+/// detail. Tiles are listed row by row from north to south, west to east
+/// within a row; for a box crossing the antimeridian the western range
+/// comes first in each row.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the covering tiles.</returns>
+func TilesForBoundingBox(box [4]float64, levelOfDetail uint) []string {
+	ranges := boxTileRanges(box, levelOfDetail)
+	if len(ranges) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, CountTilesForBoundingBox(box, levelOfDetail))
+	visitTileRanges(ranges, levelOfDetail, func(quadKey string) error {
+		keys = append(keys, quadKey)
+		return nil
+	})
+	return keys
+}
+
+/// <summary>
+/// Checks the arguments shared by the bounding box covering functions.
+/// </summary>
+/// <param name="minLat">Southern edge of the box, in degrees.</param>
+/// <param name="maxLat">Northern edge of the box, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail of the covering.</param>
+/// <returns>An error if the level is above MaxLevel or minLat is greater
+/// than maxLat.</returns>
+func checkCoverArguments(minLat float64, maxLat float64, levelOfDetail uint) error {
+	if err := validLevel(levelOfDetail); err != nil {
+		return err
+	}
+	if minLat > maxLat {
+		return fmt.Errorf("minimum latitude %v above maximum latitude %v", minLat, maxLat)
+	}
+	return nil
+}
+
+/// <summary>
+/// Determines how many tiles CoverBoundingBox would return, without
+/// building any QuadKeys, so over-broad requests can be turned away up
+/// front. The count is the width times the height of the tile range,
+/// summed over both ranges of a box crossing the antimeridian.
+/// </summary>
+/// <param name="minLat">Southern edge of the box, in degrees.</param>
+/// <param name="minLong">Western edge of the box, in degrees.</param>
+/// <param name="maxLat">Northern edge of the box, in degrees.</param>
+/// <param name="maxLong">Eastern edge of the box, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>The number of covering tiles, and an error if the level is
+/// above MaxLevel or minLat is greater than maxLat.</returns>
+func CoverCount(minLat float64, minLong float64, maxLat float64, maxLong float64, levelOfDetail uint) (int, error) {
+	if err := checkCoverArguments(minLat, maxLat, levelOfDetail); err != nil {
+		return 0, err
+	}
+	return int(CountTilesForBoundingBox([4]float64{minLat, minLong, maxLat, maxLong}, levelOfDetail)), nil
+}
+
+/// <summary>
+/// Determines the QuadKeys of every tile intersecting a bounding box at a
+/// specified level of detail, in the order of TilesForBoundingBox. The tile
+/// range is found from the box's corners; a box whose minimum longitude is
+/// greater than its maximum longitude crosses the antimeridian and is split
+/// into a western and an eastern range. To keep a high-level request from
+/// building an enormous slice, boxes needing more than MaxCoverTiles tiles
+/// are rejected; CountTilesForBoundingBox gives the count up front.
+/// </summary>
+/// <param name="minLat">Southern edge of the box, in degrees.</param>
+/// <param name="minLong">Western edge of the box, in degrees.</param>
+/// <param name="maxLat">Northern edge of the box, in degrees.</param>
+/// <param name="maxLong">Eastern edge of the box, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the covering tiles, and an error if the level
+/// is above MaxLevel, minLat is greater than maxLat, or the box needs more
+/// than MaxCoverTiles tiles.</returns>
+func CoverBoundingBox(minLat float64, minLong float64, maxLat float64, maxLong float64, levelOfDetail uint) ([]string, error) {
+	if err := checkCoverArguments(minLat, maxLat, levelOfDetail); err != nil {
+		return nil, err
+	}
+	box := [4]float64{minLat, minLong, maxLat, maxLong}
+	if count := CountTilesForBoundingBox(box, levelOfDetail); count > MaxCoverTiles {
+		return nil, fmt.Errorf("bounding box needs %d tiles at level %d, more than %d", count, levelOfDetail, MaxCoverTiles)
+	}
+	return TilesForBoundingBox(box, levelOfDetail), nil
+}
+
+/// <summary>
+/// Streams the QuadKeys of every tile intersecting a bounding box at a
+/// specified level of detail to a callback, so coverings too large to hold
+/// in memory can be written straight to disk or a database. Tiles are
+/// visited row-major: by tile Y from north to south, then by tile X from
+/// west to east, the western range first in each row for a box crossing
+/// the antimeridian. Iteration stops at the first error the callback
+/// returns. There is no tile limit.
+/// </summary>
+/// <param name="minLat">Southern edge of the box, in degrees.</param>
+/// <param name="minLong">Western edge of the box, in degrees.</param>
+/// <param name="maxLat">Northern edge of the box, in degrees.</param>
+/// <param name="maxLong">Eastern edge of the box, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <param name="fn">Function called with each tile's QuadKey.</param>
+/// <returns>The error returned by fn, or an error if the level is above
+/// MaxLevel or minLat is greater than maxLat.</returns>
+func CoverBoundingBoxFunc(minLat float64, minLong float64, maxLat float64, maxLong float64, levelOfDetail uint, fn func(quadKey string) error) error {
+	if err := checkCoverArguments(minLat, maxLat, levelOfDetail); err != nil {
+		return err
+	}
+	return visitTileRanges(boxTileRanges([4]float64{minLat, minLong, maxLat, maxLong}, levelOfDetail), levelOfDetail, fn)
+}
+
+/// <summary>
+/// Determines the deepest uniform level of detail at which the tiles
+/// covering a bounding box fit within a tile budget, for laying the box out
+/// as a simple single-level grid. If even level 1 exceeds the budget the
+/// result is level 0 and its single world tile.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="maxTiles">Maximum number of tiles.</param>
+/// <param name="levelOfDetail">Output parameter receiving the chosen level of detail.</param>
+/// <param name="keys">Output parameter receiving the QuadKeys of the covering tiles.</param>
+func BestUniformLevel(box [4]float64, maxTiles int) (levelOfDetail uint, keys []string) {
+	for level := uint(1); level <= MaxLevel; level++ {
+		if maxTiles < 1 || CountTilesForBoundingBox(box, level) > uint64(maxTiles) {
+			break
+		}
+		levelOfDetail = level
+	}
+	if levelOfDetail == 0 {
+		return 0, []string{""}
+	}
+	return levelOfDetail, TilesForBoundingBox(box, levelOfDetail)
+}
+
+/// <summary>
+/// Describes the tile coverage of a bounding box at one level of detail.
+/// </summary>
+type CoverageStat struct {
+	Level     uint
+	TileCount uint64
+	// Approximate tile edge length at the box's center latitude.
+	TileEdgeMeters float64
+}
+
+/// <summary>
+/// Reports, for each level of detail in a range, how many tiles cover a
+/// bounding box and how large those tiles are on the ground at the box's
+/// center latitude. This is the trade-off table between few large tiles
+/// and many small ones used to choose a level.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="minLevel">Coarsest level of detail to report.</param>
+/// <param name="maxLevel">Deepest level of detail to report, at most MaxLevel.</param>
+/// <returns>One entry per level from minLevel to maxLevel, or nil if the
+/// range is empty or exceeds MaxLevel.</returns>
+func CoverageStats(box [4]float64, minLevel uint, maxLevel uint) []CoverageStat {
+	if minLevel > maxLevel || maxLevel > MaxLevel {
+		return nil
+	}
+	centerLatitude := (box[0] + box[2]) / 2
+	stats := make([]CoverageStat, 0, maxLevel-minLevel+1)
+	for level := minLevel; level <= maxLevel; level++ {
+		stats = append(stats, CoverageStat{
+			Level:          level,
+			TileCount:      CountTilesForBoundingBox(box, level),
+			TileEdgeMeters: TileGroundSize(centerLatitude, level),
+		})
+	}
+	return stats
+}
+
+/// <summary>
+/// Tiles a bounding box at a constant ground resolution rather than a
+/// constant level. Because tiles shrink on the ground away from the
+/// equator, rows nearer the poles reach the target meters per pixel at a
+/// coarser level, so the output is a mixed-level covering whose rows get
+/// deeper toward the equator. Starting from the world tile, each tile
+/// intersecting the box is split until its level reaches the level needed
+/// at the latitude, within the box, closest to the equator; tiles do not
+/// overlap. A target that is not positive has no level, and a covering
+/// that would need more than MaxCoverTiles tiles is abandoned; both yield
+/// nil.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="targetMetersPerPixel">Required ground resolution, in meters
+/// per pixel.</param>
+/// <returns>The QuadKeys of the covering tiles, sorted, or nil if the box
+/// or target is invalid or the covering exceeds MaxCoverTiles.</returns>
+func AdaptiveLevelTilesForBox(box [4]float64, targetMetersPerPixel float64) []string {
+	if box[0] > box[2] || !(targetMetersPerPixel > 0) {
+		return nil
+	}
+
+	var keys []string
+	pending := []string{""}
+	for len(pending) > 0 {
+		quadKey := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+		if !inTileRanges(boxTileRanges(box, levelOfDetail), tileX, tileY) {
+			continue
+		}
+
+		bounds := tileBounds(tileX, tileY, levelOfDetail)
+		latitude := clip(0, math.Max(bounds[0], box[0]), math.Min(bounds[2], box[2]))
+		if levelOfDetail >= levelForGroundResolution(latitude, targetMetersPerPixel) {
+			keys = append(keys, quadKey)
+			continue
+		}
+		pending = append(pending, quadKey+"0", quadKey+"1", quadKey+"2", quadKey+"3")
+		if len(keys)+len(pending) > MaxCoverTiles {
+			return nil
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/// <summary>
+/// Determines the smallest tile containing a bounding box: the common
+/// prefix of the QuadKeys of its corners at MaxLevel.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in degrees.</param>
+/// <returns>The QuadKey of the enclosing tile.</returns>
+func enclosingQuadKey(box [4]float64) string {
+	northWest := LatLongToQuadKey(box[2], box[1], MaxLevel)
+	southEast := LatLongToQuadKey(box[0], box[3], MaxLevel)
+	return commonPrefix(northWest, southEast)
+}
+
+/// <summary>
+/// Determines the smallest tile containing a set of points with a margin
+/// of breathing room around them. The points' bounding box is grown by
+/// marginMeters on every side, converting meters to degrees on the sphere
+/// at the box's most poleward latitude; a single point becomes a box of
+/// side 2 * marginMeters. If the grown box reaches past the antimeridian
+/// the points are treated as globally spread and the root (empty) QuadKey
+/// is returned with no error; the empty QuadKey always means the world
+/// tile, never invalid input.
+/// </summary>
+/// <param name="lats">Latitudes of the points, in degrees.</param>
+/// <param name="lons">Longitudes of the points, in degrees.</param>
+/// <param name="marginMeters">Margin to add around the points, in meters.</param>
+/// <returns>The QuadKey of the enclosing tile, and an error if there are no
+/// points, lats and lons differ in length, a coordinate is NaN or the
+/// margin is negative or NaN.</returns>
+func PaddedEnclosingQuadKey(lats []float64, lons []float64, marginMeters float64) (string, error) {
+	if len(lats) != len(lons) {
+		return "", fmt.Errorf("%d latitudes but %d longitudes", len(lats), len(lons))
+	}
+	if len(lats) == 0 {
+		return "", errors.New("no points to enclose")
+	}
+	if !(marginMeters >= 0) {
+		return "", fmt.Errorf("invalid margin %v meters", marginMeters)
+	}
+	for i := range lats {
+		if math.IsNaN(lats[i]) || math.IsNaN(lons[i]) {
+			return "", fmt.Errorf("point %d has a NaN coordinate", i)
+		}
+	}
+	box := [4]float64{lats[0], lons[0], lats[0], lons[0]}
+	for i := range lats {
+		box[0] = math.Min(box[0], lats[i])
+		box[1] = math.Min(box[1], lons[i])
+		box[2] = math.Max(box[2], lats[i])
+		box[3] = math.Max(box[3], lons[i])
+	}
+
+	marginLat := marginMeters / EarthRadius * 180 / math.Pi
+	poleward := clip(math.Max(math.Abs(box[0]), math.Abs(box[2]))+marginLat, 0, MaxLatitude)
+	marginLong := marginLat / math.Cos(poleward*math.Pi/180)
+	box[0] = clip(box[0]-marginLat, MinLatitude, MaxLatitude)
+	box[2] = clip(box[2]+marginLat, MinLatitude, MaxLatitude)
+	box[1] -= marginLong
+	box[3] += marginLong
+	if box[1] < MinLongitude || box[3] > MaxLongitude {
+		return "", nil
+	}
+	return enclosingQuadKey(box), nil
+}
+
+/// <summary>
+/// Determines the tight tile rectangle covering a set of points at a
+/// specified level of detail, for when the power-of-two quadrant of an
+/// enclosing QuadKey is too coarse a fit. Longitudes are not wrapped: points
+/// on both sides of the antimeridian produce a rectangle stretching across
+/// the map between them, up to its full width.
+/// </summary>
+/// <param name="lats">Latitudes of the points, in degrees.</param>
+/// <param name="lons">Longitudes of the points, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="minX">Output parameter receiving the westernmost tile X coordinate.</param>
+/// <param name="minY">Output parameter receiving the northernmost tile Y coordinate.</param>
+/// <param name="maxX">Output parameter receiving the easternmost tile X coordinate.</param>
+/// <param name="maxY">Output parameter receiving the southernmost tile Y
+/// coordinate. All four are -1 if there are no points or lats and lons
+/// differ in length.</param>
+func BoundingTileRect(lats []float64, lons []float64, levelOfDetail uint) (minX int, minY int, maxX int, maxY int) {
+	if len(lats) != len(lons) || len(lats) == 0 {
+		return -1, -1, -1, -1
+	}
+	for i := range lats {
+		pixelX, pixelY := LatLongToPixelXY(lats[i], lons[i], levelOfDetail)
+		tileX, tileY := PixelXYToTileXY(pixelX, pixelY)
+		if i == 0 {
+			minX, minY, maxX, maxY = tileX, tileY, tileX, tileY
+			continue
+		}
+		if tileX < minX {
+			minX = tileX
+		}
+		if tileY < minY {
+			minY = tileY
+		}
+		if tileX > maxX {
+			maxX = tileX
+		}
+		if tileY > maxY {
+			maxY = tileY
+		}
+	}
+	return
+}
+
+/// <summary>
+/// Tracks the tile coverage of a bounding box that changes from frame to
+/// frame, such as a drag-to-select rectangle, and reports only the newly
+/// covered tiles. The zero value is ready to use; the only state kept is
+/// the previous frame's tile set. A GrowingCoverage is not safe for
+/// concurrent use.
+/// </summary>
+type GrowingCoverage struct {
+	previous map[string]bool
+}
+
+/// <summary>
+/// Replaces the tracked bounding box and returns the tiles covering it that
+/// did not cover the previous box. Tiles that drop out of the coverage are
+/// forgotten, so they are reported again if the box later regrows over
+/// them; changing the level reports the whole new coverage.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the newly covered tiles, in TilesForBoundingBox order.</returns>
+func (g *GrowingCoverage) SetBox(box [4]float64, levelOfDetail uint) []string {
+	keys := TilesForBoundingBox(box, levelOfDetail)
+	current := make(map[string]bool, len(keys))
+	var added []string
+	for _, quadKey := range keys {
+		current[quadKey] = true
+		if !g.previous[quadKey] {
+			added = append(added, quadKey)
+		}
+	}
+	g.previous = current
+	return added
+}
+
+/// <summary>
+/// Maps each tile covering a bounding box at a coarse level of detail to
+/// the tiles at a finer level that it contains, which is what a
+/// multi-level tile cache needs to fetch when the user zooms into one
+/// coarse tile. Only fine tiles that themselves intersect the box are
+/// included, so coarse tiles on the box's edge list fewer than
+/// 4^(fineLevel - coarseLevel) fine tiles.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="coarseLevel">Level of detail of the map's keys.</param>
+/// <param name="fineLevel">Level of detail of the map's values, at least coarseLevel.</param>
+/// <returns>The fine QuadKeys, sorted, keyed by coarse QuadKey, or nil if
+/// fineLevel is less than coarseLevel.</returns>
+func LevelCoverageDelta(box [4]float64, coarseLevel uint, fineLevel uint) map[string][]string {
+	if fineLevel < coarseLevel {
+		return nil
+	}
+	perCoarseTile := make(map[string][]string)
+	for _, quadKey := range TilesForBoundingBox(box, coarseLevel) {
+		perCoarseTile[quadKey] = nil
+	}
+	for _, quadKey := range TilesForBoundingBox(box, fineLevel) {
+		ancestor := quadKey[:coarseLevel]
+		perCoarseTile[ancestor] = append(perCoarseTile[ancestor], quadKey)
+	}
+	for _, keys := range perCoarseTile {
+		sort.Strings(keys)
+	}
+	return perCoarseTile
+}
+
+/// <summary>
+/// Determines what fraction of the tiles covering a bounding box are
+/// present in a tile set, such as the progress of a job seeding the box's
+/// tiles. Present tiles outside the box or at another level are ignored.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="present">QuadKeys of the tiles already present.</param>
+/// <returns>The fraction from 0 to 1, or 0 if no tiles cover the box.</returns>
+func CoverageCompleteness(box [4]float64, levelOfDetail uint, present []string) float64 {
+	required := CountTilesForBoundingBox(box, levelOfDetail)
+	if required == 0 {
+		return 0
+	}
+	ranges := boxTileRanges(box, levelOfDetail)
+	found := make(map[string]bool)
+	for _, quadKey := range present {
+		if uint(len(quadKey)) != levelOfDetail || !validQuadKey(quadKey) {
+			continue
+		}
+		tileX, tileY, _ := QuadKeyToTileXY(quadKey)
+		if inTileRanges(ranges, tileX, tileY) {
+			found[quadKey] = true
+		}
+	}
+	return float64(len(found)) / float64(required)
+}
+
+/// <summary>
+/// Determines the tiles covering a bounding box that are not yet in a tile
+/// set: the worklist for resuming an interrupted seeding job. Present
+/// tiles outside the box or at another level do not affect the result.
+/// The tiles are returned in Z-order, which for keys of one level is plain
+/// sorted order and keeps nearby tiles together for fetch locality.
+/// </summary>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="present">QuadKeys of the tiles already present.</param>
+/// <returns>The QuadKeys of the missing tiles, in Z-order.</returns>
+func MissingTiles(box [4]float64, levelOfDetail uint, present []string) []string {
+	have := make(map[string]bool, len(present))
+	for _, quadKey := range present {
+		have[quadKey] = true
+	}
+	var missing []string
+	for _, quadKey := range TilesForBoundingBox(box, levelOfDetail) {
+		if !have[quadKey] {
+			missing = append(missing, quadKey)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+/// <summary>
+/// Determines the tiles covering the extent of a raster described by a
+/// GDAL-style geotransform. The geotransform elements are, in order: the X
+/// of the raster's upper-left corner, the pixel width, the row rotation,
+/// the Y of the upper-left corner, the column rotation and the pixel
+/// height (negative for north-up rasters), so pixel (col, row) maps to
+/// X = gt[0] + col*gt[1] + row*gt[2] and Y = gt[3] + col*gt[4] + row*gt[5].
+/// The four raster corners are computed this way and their bounding box is
+/// covered.
+/// </summary>
+/// <param name="geoTransform">The geotransform.</param>
+/// <param name="widthPx">Raster width, in pixels.</param>
+/// <param name="heightPx">Raster height, in pixels.</param>
+/// <param name="srcIsLatLong">True if X and Y are longitude and latitude in
+/// degrees; false if they are EPSG:3857 meters.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the covering tiles, in TilesForBoundingBox order.</returns>
+func TilesForGeoTransform(geoTransform [6]float64, widthPx int, heightPx int, srcIsLatLong bool, levelOfDetail uint) []string {
+	box := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, corner := range [][2]float64{{0, 0}, {float64(widthPx), 0}, {0, float64(heightPx)}, {float64(widthPx), float64(heightPx)}} {
+		x := geoTransform[0] + corner[0]*geoTransform[1] + corner[1]*geoTransform[2]
+		y := geoTransform[3] + corner[0]*geoTransform[4] + corner[1]*geoTransform[5]
+		latitude, longitude := y, x
+		if !srcIsLatLong {
+			latitude, longitude = MetersToLatLong(x, y)
+		}
+		box[0] = math.Min(box[0], latitude)
+		box[1] = math.Min(box[1], longitude)
+		box[2] = math.Max(box[2], latitude)
+		box[3] = math.Max(box[3], longitude)
+	}
+	return TilesForBoundingBox(box, levelOfDetail)
+}
+
+/// <summary>
+/// Checks that a tile set is exactly the coverage of a bounding box at a
+/// level of detail, with no gaps and no extras, such as a CI check that a
+/// stored tile manifest still matches its declared region. Missing tiles
+/// are those MissingTiles reports; extras are tiles in the set that do not
+/// intersect the box, including keys at another level or invalid keys.
+/// </summary>
+/// <param name="keys">QuadKeys of the tile set.</param>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="ok">Output parameter receiving true if nothing is missing or extra.</param>
+/// <param name="missing">Output parameter receiving the missing QuadKeys, in Z-order.</param>
+/// <param name="extra">Output parameter receiving the extra QuadKeys, sorted.</param>
+func ExactlyCovers(keys []string, box [4]float64, levelOfDetail uint) (ok bool, missing []string, extra []string) {
+	missing = MissingTiles(box, levelOfDetail, keys)
+
+	required := make(map[string]bool)
+	for _, quadKey := range TilesForBoundingBox(box, levelOfDetail) {
+		required[quadKey] = true
+	}
+	seen := make(map[string]bool)
+	for _, quadKey := range keys {
+		if !required[quadKey] && !seen[quadKey] {
+			seen[quadKey] = true
+			extra = append(extra, quadKey)
+		}
+	}
+	sort.Strings(extra)
+	return len(missing) == 0 && len(extra) == 0, missing, extra
+}
+
+/// <summary>
+/// Determines the tiles of a bounding box that are not part of a region,
+/// the inverse mask of the region for "everywhere except here" queries.
+/// The complement is clamped to the box: only tiles covering the box are
+/// candidates, and region tiles outside the box are ignored.
+/// </summary>
+/// <param name="regionKeys">QuadKeys of the region's tiles at levelOfDetail.</param>
+/// <param name="box">Bounding box as {minLat, minLong, maxLat, maxLong}, in
+/// degrees; minLong greater than maxLong crosses the antimeridian.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the complement, in Z-order.</returns>
+func ComplementTiles(regionKeys []string, box [4]float64, levelOfDetail uint) []string {
+	return MissingTiles(box, levelOfDetail, regionKeys)
+}
+
+/// <summary>
+/// A feature to tile: its bounding box and the level of detail it needs.
+/// </summary>
+type Feature struct {
+	Box   [4]float64
+	Level uint
+}
+
+/// <summary>
+/// Determines the tiles covering each of a collection of features, each at
+/// its own level of detail, so a heterogeneous feature collection can be
+/// tiled in one call. Features are tiled with CoverBoundingBox by a pool of
+/// GOMAXPROCS workers. A feature whose level is above MaxLevel, whose
+/// southern edge is north of its northern edge, or which needs more than
+/// MaxCoverTiles tiles is rejected and has no entry in the result. Tiles
+/// are distinct within a feature but may repeat across features.
+/// </summary>
+/// <param name="features">The features to tile, each a bounding box
+/// {minLat, minLong, maxLat, maxLong} and the level of detail it needs.</param>
+/// <returns>A map from each accepted feature's index to the QuadKeys of its
+/// covering tiles.</returns>
+func TilesForFeatures(features []Feature) map[int][]string {
+	results := make([][]string, len(features))
+	valid := make([]bool, len(features))
+	indexes := make(chan int)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(features) {
+		workers = len(features)
+	}
+	var wait sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for i := range indexes {
+				box := features[i].Box
+				keys, err := CoverBoundingBox(box[0], box[1], box[2], box[3], features[i].Level)
+				results[i], valid[i] = keys, err == nil
+			}
+		}()
+	}
+	for i := range features {
+		indexes <- i
+	}
+	close(indexes)
+	wait.Wait()
+
+	tiles := make(map[int][]string, len(features))
+	for i, keys := range results {
+		if valid[i] {
+			tiles[i] = keys
+		}
+	}
+	return tiles
+}
+
+/// <summary>
+/// An inclusive rectangle of tiles at one level of detail, a compact
+/// stand-in for the QuadKeys it covers.
+/// </summary>
+type TileRange struct {
+	MinX, MinY, MaxX, MaxY int
+	Level                  uint
+}
+
+/// <summary>
+/// Determines the number of tiles in the range.
+/// </summary>
+/// <returns>The tile count, 0 for an empty range.</returns>
+func (r TileRange) Count() int {
+	if r.MaxX < r.MinX || r.MaxY < r.MinY {
+		return 0
+	}
+	return (r.MaxX - r.MinX + 1) * (r.MaxY - r.MinY + 1)
+}
+
+/// <summary>
+/// Determines whether the range contains a tile of its level.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <returns>True if the tile is in the range.</returns>
+func (r TileRange) Contains(tileX int, tileY int) bool {
+	return tileX >= r.MinX && tileX <= r.MaxX && tileY >= r.MinY && tileY <= r.MaxY
+}
+
+/// <summary>
+/// Visits every tile of the range, row by row from north to south and west
+/// to east within a row.
+/// </summary>
+/// <param name="fn">Function called with each tile.</param>
+func (r TileRange) Each(fn func(Tile)) {
+	for tileY := r.MinY; tileY <= r.MaxY; tileY++ {
+		for tileX := r.MinX; tileX <= r.MaxX; tileX++ {
+			fn(Tile{X: tileX, Y: tileY, Level: r.Level})
+		}
+	}
+}
+
+/// <summary>
+/// Determines the tile ranges covering a bounding box at a specified level
+/// of detail, without building any QuadKeys. A box whose minimum longitude
+/// is greater than its maximum longitude crosses the antimeridian and
+/// yields two ranges, western first, unless together they span the whole
+/// map width, in which case they merge into one.
+/// </summary>
+/// <param name="minLat">Southern edge of the box, in degrees.</param>
+/// <param name="minLong">Western edge of the box, in degrees.</param>
+/// <param name="maxLat">Northern edge of the box, in degrees.</param>
+/// <param name="maxLong">Eastern edge of the box, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 0 (the world tile)
+/// to 23 (highest detail).</param>
+/// <returns>The one or two covering ranges, and an error if the level is
+/// above MaxLevel or minLat is greater than maxLat.</returns>
+func CoverBoundingBoxRange(minLat float64, minLong float64, maxLat float64, maxLong float64, levelOfDetail uint) ([]TileRange, error) {
+	if err := checkCoverArguments(minLat, maxLat, levelOfDetail); err != nil {
+		return nil, err
+	}
+	var ranges []TileRange
+	for _, r := range boxTileRanges([4]float64{minLat, minLong, maxLat, maxLong}, levelOfDetail) {
+		ranges = append(ranges, TileRange{MinX: r[0], MinY: r[1], MaxX: r[2], MaxY: r[3], Level: levelOfDetail})
+	}
+	return ranges, nil
+}
@@ -0,0 +1,100 @@
+// Quadkeys project coverage_test.go
+package Quadkeys
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTilesForFeatures(t *testing.T) {
+	features := []Feature{
+		{Box: [4]float64{47.5, -122.5, 47.7, -122.2}, Level: 10},
+		{Box: [4]float64{-10, 170, 10, -170}, Level: 6},
+		{Box: [4]float64{0, 0, 1, 1}, Level: MaxLevel + 1},
+		{Box: [4]float64{10, 0, 0, 1}, Level: 5},
+		{Box: [4]float64{-80, -179, 80, 179}, Level: MaxLevel},
+		{Box: [4]float64{-85, -180, 85, 180}, Level: 0},
+	}
+	tiles := TilesForFeatures(features)
+	for _, i := range []int{0, 1, 5} {
+		want := TilesForBoundingBox(features[i].Box, features[i].Level)
+		if got, ok := tiles[i]; !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("feature %d: got %v, want %v", i, got, want)
+		}
+	}
+	for _, i := range []int{2, 3, 4} {
+		if got, ok := tiles[i]; ok {
+			t.Errorf("feature %d should be rejected, got %d tiles", i, len(got))
+		}
+	}
+	if len(TilesForFeatures(nil)) != 0 {
+		t.Error("TilesForFeatures(nil) should be empty")
+	}
+}
+
+func TestAdaptiveLevelTilesForBox(t *testing.T) {
+	box := [4]float64{47.5, -122.5, 47.7, -122.2}
+	keys := AdaptiveLevelTilesForBox(box, 150)
+	if len(keys) == 0 {
+		t.Fatal("AdaptiveLevelTilesForBox returned no tiles")
+	}
+	for _, quadKey := range keys {
+		minLat, _, maxLat, _, err := QuadKeyToBoundingBox(quadKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latitude := clip(0, math.Max(minLat, box[0]), math.Min(maxLat, box[2]))
+		if resolution := GroundResolution(latitude, uint(len(quadKey))); resolution > 150 {
+			t.Errorf("tile %q resolves %v meters per pixel, coarser than 150", quadKey, resolution)
+		}
+	}
+}
+
+func TestAdaptiveLevelTilesForBoxLimits(t *testing.T) {
+	box := [4]float64{-60, -170, 60, 170}
+	for _, target := range []float64{0, -1, math.NaN(), math.Inf(-1)} {
+		if keys := AdaptiveLevelTilesForBox(box, target); keys != nil {
+			t.Errorf("AdaptiveLevelTilesForBox with target %v returned %d tiles, want nil", target, len(keys))
+		}
+	}
+	if keys := AdaptiveLevelTilesForBox(box, 1); keys != nil {
+		t.Errorf("AdaptiveLevelTilesForBox past MaxCoverTiles returned %d tiles, want nil", len(keys))
+	}
+}
+
+func TestPaddedEnclosingQuadKey(t *testing.T) {
+	lats := []float64{47.60, 47.62}
+	lons := []float64{-122.34, -122.32}
+	quadKey, err := PaddedEnclosingQuadKey(lats, lons, 100)
+	if err != nil || quadKey == "" {
+		t.Fatalf("PaddedEnclosingQuadKey = %q, %v", quadKey, err)
+	}
+	for i := range lats {
+		if inside, _ := Contains(quadKey, lats[i], lons[i]); !inside {
+			t.Errorf("%q does not contain point %d", quadKey, i)
+		}
+	}
+	wide, err := PaddedEnclosingQuadKey(lats, lons, 100000)
+	if err != nil || !(wide == quadKey || IsAncestor(wide, quadKey)) {
+		t.Errorf("wider margin gave %q, %v, want %q or an ancestor", wide, err, quadKey)
+	}
+
+	if root, err := PaddedEnclosingQuadKey([]float64{0}, []float64{179.99}, 10000); err != nil || root != "" {
+		t.Errorf("box past the antimeridian = %q, %v, want the root", root, err)
+	}
+	for _, c := range []struct {
+		lats, lons []float64
+		margin     float64
+	}{
+		{nil, nil, 0},
+		{[]float64{1, 2}, []float64{1}, 0},
+		{[]float64{1}, []float64{1}, -5},
+		{[]float64{1}, []float64{1}, math.NaN()},
+		{[]float64{math.NaN()}, []float64{1}, 0},
+	} {
+		if quadKey, err := PaddedEnclosingQuadKey(c.lats, c.lons, c.margin); err == nil {
+			t.Errorf("PaddedEnclosingQuadKey(%v, %v, %v) = %q, want an error", c.lats, c.lons, c.margin, quadKey)
+		}
+	}
+}
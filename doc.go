@@ -2,5 +2,19 @@
 
 /*
 Quadkeys document
+
+Levels of detail run from 0 to MaxLevel. The Bing Maps tile system this
+package follows starts at level 1, but level 0 is supported throughout as
+the single TileSize x TileSize tile covering the whole world: its QuadKey is
+the empty string, QuadKeyToBoundingBox("") spans the full Mercator extent,
+and Children("") returns the four level 1 tiles. Level parameters documented
+as running from 1 to 23 accept 0 as well.
+
+The package holds no mutable state and needs no initialization: every
+function works only on its arguments, so all of them are safe to call from
+many goroutines at once. Types with state, such as OccupiedTiles and
+Converter, document their own concurrency guarantees. For conversions that
+must not allocate, AppendQuadKey builds QuadKeys into a caller's buffer and
+Converter writes them straight to an io.Writer from pooled buffers.
 */
 package Quadkeys
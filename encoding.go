@@ -0,0 +1,232 @@
+// Quadkeys project encoding.go
+package Quadkeys
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/// <summary>
+/// Encodes a QuadKey as runs of repeated digits, written as digit "x" count
+/// and separated by spaces, so "3333311" becomes "3x5 1x2". This shrinks
+/// logs that carry many QuadKeys with long runs of the same digit.
+/// </summary>
+/// <param name="quadKey">QuadKey to encode.</param>
+/// <returns>The run-length encoded form, or an empty string if the QuadKey
+/// is empty or invalid.</returns>
+func RunLengthEncodeQuadKey(quadKey string) string {
+	if !validQuadKey(quadKey) {
+		return ""
+	}
+	var runs []string
+	for i := 0; i < len(quadKey); {
+		j := i
+		for j < len(quadKey) && quadKey[j] == quadKey[i] {
+			j++
+		}
+		runs = append(runs, quadKey[i:i+1]+"x"+strconv.Itoa(j-i))
+		i = j
+	}
+	return strings.Join(runs, " ")
+}
+
+/// <summary>
+/// Decodes a QuadKey produced by RunLengthEncodeQuadKey.
+/// </summary>
+/// <param name="encoded">Run-length encoded QuadKey.</param>
+/// <returns>The QuadKey, or an error if a run is malformed or the decoded
+/// key is longer than MaxLevel.</returns>
+func RunLengthDecodeQuadKey(encoded string) (string, error) {
+	var builder strings.Builder
+	for _, run := range strings.Fields(encoded) {
+		if len(run) < 3 || run[0] < '0' || run[0] > '3' || run[1] != 'x' {
+			return "", fmt.Errorf("invalid quadkey run %q", run)
+		}
+		count, err := strconv.Atoi(run[2:])
+		if err != nil || count < 1 {
+			return "", fmt.Errorf("invalid quadkey run %q", run)
+		}
+		if builder.Len()+count > MaxLevel {
+			return "", fmt.Errorf("decoded quadkey is longer than %d digits", MaxLevel)
+		}
+		builder.WriteString(strings.Repeat(run[:1], count))
+	}
+	return builder.String(), nil
+}
+
+const (
+	denseTileSetVersion  = 1
+	denseContainerArray  = 0
+	denseContainerBitmap = 1
+	denseArrayMaxCount   = 4096
+	denseBitmapBytes     = 1 << 16 / 8
+)
+
+/// <summary>
+/// Encodes a set of tiles at one level of detail into a compact binary
+/// form modelled on roaring bitmaps, for storing dense coverage masks.
+/// Each tile is mapped to its Z-order index; indices are grouped into
+/// containers by their high bits (index >> 16), and each container stores
+/// the low 16 bits either as a sorted array or, once it holds more than
+/// 4096 tiles, as a 65536-bit bitmap, whichever is smaller.
+///
+/// Layout: a version byte (1), a level byte, the container count as a
+/// uvarint, then per container the difference between its high bits and
+/// the previous container's as a uvarint, a kind byte (0 array, 1 bitmap)
+/// and either a uvarint count followed by that many little-endian uint16
+/// values, or 8192 bitmap bytes, least significant bit first.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles, all at levelOfDetail.
+/// Duplicates are stored once.</param>
+/// <param name="levelOfDetail">Level of detail of the tiles, at most MaxLevel.</param>
+/// <returns>The encoded set, or an error if the level is out of range or a
+/// key is invalid or at another level.</returns>
+func EncodeDenseTileSet(keys []string, levelOfDetail uint) ([]byte, error) {
+	if levelOfDetail > MaxLevel {
+		return nil, fmt.Errorf("level %d exceeds maximum level %d", levelOfDetail, MaxLevel)
+	}
+	indices := make([]uint64, 0, len(keys))
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) || uint(len(quadKey)) != levelOfDetail {
+			return nil, fmt.Errorf("invalid quadkey %q for level %d", quadKey, levelOfDetail)
+		}
+		indices = append(indices, quadKeyToZIndex(quadKey))
+	}
+	sort.Slice(indices, func(i int, j int) bool { return indices[i] < indices[j] })
+
+	type container struct {
+		high uint64
+		lows []uint16
+	}
+	var containers []container
+	for i, index := range indices {
+		if i > 0 && index == indices[i-1] {
+			continue
+		}
+		high := index >> 16
+		if len(containers) == 0 || containers[len(containers)-1].high != high {
+			containers = append(containers, container{high: high})
+		}
+		last := &containers[len(containers)-1]
+		last.lows = append(last.lows, uint16(index))
+	}
+
+	data := []byte{denseTileSetVersion, byte(levelOfDetail)}
+	data = binary.AppendUvarint(data, uint64(len(containers)))
+	previous := uint64(0)
+	for _, c := range containers {
+		data = binary.AppendUvarint(data, c.high-previous)
+		previous = c.high
+		if len(c.lows) <= denseArrayMaxCount {
+			data = append(data, denseContainerArray)
+			data = binary.AppendUvarint(data, uint64(len(c.lows)))
+			for _, low := range c.lows {
+				data = binary.LittleEndian.AppendUint16(data, low)
+			}
+		} else {
+			data = append(data, denseContainerBitmap)
+			bitmap := make([]byte, denseBitmapBytes)
+			for _, low := range c.lows {
+				bitmap[low>>3] |= 1 << (low & 7)
+			}
+			data = append(data, bitmap...)
+		}
+	}
+	return data, nil
+}
+
+/// <summary>
+/// Decodes a tile set produced by EncodeDenseTileSet.
+/// </summary>
+/// <param name="data">The encoded set.</param>
+/// <param name="keys">Output parameter receiving the QuadKeys of the tiles, in Z-order.</param>
+/// <param name="levelOfDetail">Output parameter receiving the level of detail of the tiles.</param>
+/// <param name="err">Output parameter receiving an error if the data is truncated or malformed.</param>
+func DecodeDenseTileSet(data []byte) (keys []string, levelOfDetail uint, err error) {
+	if len(data) < 2 {
+		return nil, 0, errors.New("dense tile set is truncated")
+	}
+	if data[0] != denseTileSetVersion {
+		return nil, 0, fmt.Errorf("unsupported dense tile set version %d", data[0])
+	}
+	levelOfDetail = uint(data[1])
+	if levelOfDetail > MaxLevel {
+		return nil, 0, fmt.Errorf("level %d exceeds maximum level %d", levelOfDetail, MaxLevel)
+	}
+	limit := uint64(1) << (2 * levelOfDetail)
+	data = data[2:]
+
+	readUvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, errors.New("dense tile set is truncated")
+		}
+		data = data[n:]
+		return v, nil
+	}
+
+	count, err := readUvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+	high := uint64(0)
+	lastIndex := int64(-1)
+	add := func(index uint64) error {
+		if index >= limit || int64(index) <= lastIndex {
+			return errors.New("dense tile set index is out of range or order")
+		}
+		lastIndex = int64(index)
+		keys = append(keys, zIndexToQuadKey(index, levelOfDetail))
+		return nil
+	}
+	for c := uint64(0); c < count; c++ {
+		delta, err := readUvarint()
+		if err != nil {
+			return nil, 0, err
+		}
+		high += delta
+		if high >= limit || len(data) < 1 {
+			return nil, 0, errors.New("dense tile set is truncated or malformed")
+		}
+		kind := data[0]
+		data = data[1:]
+		switch kind {
+		case denseContainerArray:
+			n, err := readUvarint()
+			if err != nil {
+				return nil, 0, err
+			}
+			if uint64(len(data)) < 2*n {
+				return nil, 0, errors.New("dense tile set is truncated")
+			}
+			for i := uint64(0); i < n; i++ {
+				if err := add(high<<16 | uint64(binary.LittleEndian.Uint16(data[2*i:]))); err != nil {
+					return nil, 0, err
+				}
+			}
+			data = data[2*n:]
+		case denseContainerBitmap:
+			if len(data) < denseBitmapBytes {
+				return nil, 0, errors.New("dense tile set is truncated")
+			}
+			for low := 0; low < 1<<16; low++ {
+				if data[low>>3]&(1<<(low&7)) != 0 {
+					if err := add(high<<16 | uint64(low)); err != nil {
+						return nil, 0, err
+					}
+				}
+			}
+			data = data[denseBitmapBytes:]
+		default:
+			return nil, 0, fmt.Errorf("unknown dense tile set container kind %d", kind)
+		}
+	}
+	if len(data) != 0 {
+		return nil, 0, errors.New("dense tile set has trailing data")
+	}
+	return keys, levelOfDetail, nil
+}
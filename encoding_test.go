@@ -0,0 +1,141 @@
+// Quadkeys project encoding_test.go
+package Quadkeys
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRunLengthRoundTrip(t *testing.T) {
+	cases := []struct {
+		quadKey string
+		encoded string
+	}{
+		{"3333311", "3x5 1x2"},
+		{"0", "0x1"},
+		{"0123", "0x1 1x1 2x1 3x1"},
+		{"00000000000000000000000", "0x23"},
+		{"", ""},
+	}
+	for _, c := range cases {
+		encoded := RunLengthEncodeQuadKey(c.quadKey)
+		if encoded != c.encoded {
+			t.Errorf("RunLengthEncodeQuadKey(%q) = %q, want %q", c.quadKey, encoded, c.encoded)
+		}
+		decoded, err := RunLengthDecodeQuadKey(encoded)
+		if err != nil || decoded != c.quadKey {
+			t.Errorf("RunLengthDecodeQuadKey(%q) = %q, %v, want %q", encoded, decoded, err, c.quadKey)
+		}
+	}
+}
+
+func TestRunLengthEncodeInvalid(t *testing.T) {
+	for _, quadKey := range []string{"0124", "abc", strings.Repeat("0", MaxLevel+1)} {
+		if encoded := RunLengthEncodeQuadKey(quadKey); encoded != "" {
+			t.Errorf("RunLengthEncodeQuadKey(%q) = %q, want empty", quadKey, encoded)
+		}
+	}
+}
+
+func TestRunLengthDecodeMalformed(t *testing.T) {
+	for _, encoded := range []string{"4x1", "1x0", "1x", "1y2", "1x-1", "1xa", "x1"} {
+		if decoded, err := RunLengthDecodeQuadKey(encoded); err == nil {
+			t.Errorf("RunLengthDecodeQuadKey(%q) = %q, want error", encoded, decoded)
+		}
+	}
+}
+
+func TestRunLengthDecodeOverflow(t *testing.T) {
+	if _, err := RunLengthDecodeQuadKey("0x23"); err != nil {
+		t.Errorf("RunLengthDecodeQuadKey(%q) failed: %v", "0x23", err)
+	}
+	for _, encoded := range []string{"0x24", "0x20 1x4"} {
+		_, err := RunLengthDecodeQuadKey(encoded)
+		if err == nil || !strings.Contains(err.Error(), "longer than") {
+			t.Errorf("RunLengthDecodeQuadKey(%q) error = %v, want overflow error", encoded, err)
+		}
+	}
+}
+
+func denseRoundTrip(t *testing.T, keys []string, levelOfDetail uint) []byte {
+	t.Helper()
+	data, err := EncodeDenseTileSet(keys, levelOfDetail)
+	if err != nil {
+		t.Fatalf("EncodeDenseTileSet: %v", err)
+	}
+	decoded, level, err := DecodeDenseTileSet(data)
+	if err != nil {
+		t.Fatalf("DecodeDenseTileSet: %v", err)
+	}
+	want := make([]string, 0, len(keys))
+	seen := make(map[string]bool)
+	for _, quadKey := range keys {
+		if !seen[quadKey] {
+			seen[quadKey] = true
+			want = append(want, quadKey)
+		}
+	}
+	sort.Strings(want)
+	if level != levelOfDetail || len(decoded) != len(want) || (len(want) > 0 && !reflect.DeepEqual(decoded, want)) {
+		t.Fatalf("round trip gave level %d and %d keys, want level %d and %d keys", level, len(decoded), levelOfDetail, len(want))
+	}
+	return data
+}
+
+func TestDenseTileSetArrayContainer(t *testing.T) {
+	keys := []string{"0123", "3210", "0000", "0123", "2222"}
+	data := denseRoundTrip(t, keys, 4)
+	// Version, level, one container, high delta 0, array kind.
+	if data[4] != denseContainerArray {
+		t.Errorf("container kind = %d, want array", data[4])
+	}
+}
+
+func TestDenseTileSetBitmapContainer(t *testing.T) {
+	var keys []string
+	for i := uint64(0); i < denseArrayMaxCount+904; i++ {
+		keys = append(keys, zIndexToQuadKey(i*13%(1<<16), 8))
+	}
+	data := denseRoundTrip(t, keys, 8)
+	if data[4] != denseContainerBitmap {
+		t.Errorf("container kind = %d, want bitmap", data[4])
+	}
+	if len(data) != 5+denseBitmapBytes {
+		t.Errorf("encoded %d bytes, want %d", len(data), 5+denseBitmapBytes)
+	}
+}
+
+func TestDenseTileSetMultipleContainers(t *testing.T) {
+	var keys []string
+	// A dense first container, a sparse third and a single tile in the last.
+	for i := uint64(0); i < 5000; i++ {
+		keys = append(keys, zIndexToQuadKey(i, 12))
+	}
+	for i := uint64(0); i < 100; i++ {
+		keys = append(keys, zIndexToQuadKey(2<<16+i*7, 12))
+	}
+	keys = append(keys, zIndexToQuadKey(1<<24-1, 12))
+	denseRoundTrip(t, keys, 12)
+}
+
+func TestDenseTileSetEmptyAndWorld(t *testing.T) {
+	denseRoundTrip(t, nil, 5)
+	denseRoundTrip(t, []string{""}, 0)
+}
+
+func TestDenseTileSetErrors(t *testing.T) {
+	if _, err := EncodeDenseTileSet([]string{"012"}, 4); err == nil {
+		t.Error("EncodeDenseTileSet accepted a key at another level")
+	}
+	if _, err := EncodeDenseTileSet(nil, MaxLevel+1); err == nil {
+		t.Error("EncodeDenseTileSet accepted a level above MaxLevel")
+	}
+	data, _ := EncodeDenseTileSet([]string{"0123", "3210"}, 4)
+	for n := 0; n < len(data); n++ {
+		if _, _, err := DecodeDenseTileSet(data[:n]); err == nil {
+			t.Errorf("DecodeDenseTileSet accepted %d of %d bytes", n, len(data))
+		}
+	}
+}
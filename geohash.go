@@ -0,0 +1,107 @@
+// Quadkeys project geohash.go
+package Quadkeys
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+/// <summary>
+/// Decodes a geohash into the bounds of its cell.
+/// </summary>
+/// <param name="geohash">The geohash, in either case.</param>
+/// <returns>The cell as {minLat, minLong, maxLat, maxLong}, in degrees, or
+/// an error if the geohash is empty or contains a character outside the
+/// geohash alphabet.</returns>
+func geohashBounds(geohash string) ([4]float64, error) {
+	if geohash == "" {
+		return [4]float64{}, fmt.Errorf("empty geohash")
+	}
+	box := [4]float64{-90, -180, 90, 180}
+	longitudeBit := true
+	for i, c := range strings.ToLower(geohash) {
+		value := strings.IndexRune(geohashAlphabet, c)
+		if value < 0 {
+			return [4]float64{}, fmt.Errorf("invalid geohash character %q at index %d", c, i)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := value&(1<<uint(bit)) != 0
+			if longitudeBit {
+				mid := (box[1] + box[3]) / 2
+				if set {
+					box[1] = mid
+				} else {
+					box[3] = mid
+				}
+			} else {
+				mid := (box[0] + box[2]) / 2
+				if set {
+					box[0] = mid
+				} else {
+					box[2] = mid
+				}
+			}
+			longitudeBit = !longitudeBit
+		}
+	}
+	return box, nil
+}
+
+/// <summary>
+/// Determines every tile at a level of detail whose footprint intersects a
+/// geohash cell, for migrating geohash-indexed data to QuadKeys without
+/// losing any of it. The two grids do not nest: one geohash cell can
+/// overlap several tiles and one tile several cells, so data migrated
+/// this way may land in more than one tile. Tiles that only touch the
+/// cell's edge may be included.
+/// </summary>
+/// <param name="geohash">The geohash of the cell.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the overlapping tiles, in TilesForBoundingBox
+/// order, or nil if the geohash is invalid.</returns>
+func GeohashCellToQuadKeys(geohash string, levelOfDetail uint) []string {
+	box, err := geohashBounds(geohash)
+	if err != nil {
+		return nil
+	}
+	return TilesForBoundingBox(box, levelOfDetail)
+}
+
+/// <summary>
+/// Determines the level of detail whose tiles best match the size of
+/// geohash cells of a given precision at a latitude, so a QuadKey index can
+/// keep roughly the granularity of an existing geohash index. A geohash of
+/// precision p splits longitude ceil(5p/2) times and latitude floor(5p/2)
+/// times; the chosen level is the one whose tile edge (TileGroundSize) is
+/// closest, by ratio, to the geometric mean of the cell's ground width and
+/// height.
+/// </summary>
+/// <param name="precision">Geohash length, in characters; values below 1
+/// are treated as 1.</param>
+/// <param name="latitude">Latitude (in degrees) at which to compare sizes.</param>
+/// <returns>The level of detail, from 1 to MaxLevel.</returns>
+func GeohashPrecisionToLevel(precision int, latitude float64) uint {
+	if precision < 1 {
+		precision = 1
+	}
+	latitude = clip(latitude, MinLatitude, MaxLatitude)
+	longitudeBits := (5*precision + 1) / 2
+	latitudeBits := 5 * precision / 2
+	metersPerDegree := math.Pi * EarthRadius / 180
+	width := 360 / math.Ldexp(1, longitudeBits) * metersPerDegree * math.Cos(latitude*math.Pi/180)
+	height := 180 / math.Ldexp(1, latitudeBits) * metersPerDegree
+	cellSize := math.Sqrt(width * height)
+
+	best, bestError := uint(1), math.Inf(1)
+	for levelOfDetail := uint(1); levelOfDetail <= MaxLevel; levelOfDetail++ {
+		e := math.Abs(math.Log(TileGroundSize(latitude, levelOfDetail) / cellSize))
+		if e < bestError {
+			best, bestError = levelOfDetail, e
+		}
+	}
+	return best
+}
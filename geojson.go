@@ -0,0 +1,58 @@
+// Quadkeys project geojson.go
+package Quadkeys
+
+import (
+	"encoding/json"
+)
+
+/// <summary>
+/// A GeoJSON Polygon geometry.
+/// </summary>
+type geoJSONGeometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+/// <summary>
+/// A GeoJSON Feature.
+/// </summary>
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+/// <summary>
+/// Converts a QuadKey into a GeoJSON Feature whose geometry is the tile's
+/// footprint, for viewing tiles in tools such as geojson.io. The polygon
+/// follows RFC 7946: positions are [longitude, latitude], the ring runs
+/// counterclockwise (southwest, southeast, northeast, northwest) and is
+/// closed by repeating its first position. Tiles never straddle the
+/// antimeridian; the easternmost column ends exactly at longitude 180 and
+/// the westernmost starts at -180. The properties hold the QuadKey and its
+/// level.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The encoded Feature, and an error if the QuadKey is invalid.</returns>
+func QuadKeyToGeoJSON(quadKey string) ([]byte, error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return nil, err
+	}
+	bounds := tileBounds(tileX, tileY, levelOfDetail)
+	ring := [][2]float64{
+		{bounds[1], bounds[0]},
+		{bounds[3], bounds[0]},
+		{bounds[3], bounds[2]},
+		{bounds[1], bounds[2]},
+		{bounds[1], bounds[0]},
+	}
+	return json.Marshal(geoJSONFeature{
+		Type:     "Feature",
+		Geometry: geoJSONGeometry{Type: "Polygon", Coordinates: [][][2]float64{ring}},
+		Properties: map[string]interface{}{
+			"quadKey": quadKey,
+			"level":   levelOfDetail,
+		},
+	})
+}
@@ -0,0 +1,402 @@
+// Quadkeys project geometry.go
+package Quadkeys
+
+import (
+	"math"
+	"sort"
+)
+
+/// <summary>
+/// Determines the latitude/longitude bounds of a tile.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail of the tile.</param>
+/// <returns>The bounds as {minLat, minLong, maxLat, maxLong}, in degrees.</returns>
+func tileBounds(tileX int, tileY int, levelOfDetail uint) [4]float64 {
+	pixelX, pixelY := TileXYToPixelXY(tileX, tileY)
+	maxLat, minLong := pixelXYToLatLongFloat(float64(pixelX), float64(pixelY), levelOfDetail)
+	minLat, maxLong := pixelXYToLatLongFloat(float64(pixelX+TileSize), float64(pixelY+TileSize), levelOfDetail)
+	return [4]float64{minLat, minLong, maxLat, maxLong}
+}
+
+/// <summary>
+/// Determines the latitude/longitude of the center of a tile.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail of the tile.</param>
+/// <returns>The latitude and longitude of the center, in degrees.</returns>
+func tileCenter(tileX int, tileY int, levelOfDetail uint) (float64, float64) {
+	pixelX, pixelY := TileXYToPixelXY(tileX, tileY)
+	return PixelXYToLatLong(pixelX+TileSize/2, pixelY+TileSize/2, levelOfDetail)
+}
+
+/// <summary>
+/// Converts a QuadKey into the latitude/longitude of its tile's center, for
+/// placing labels and markers in the middle of a tile. The center is the
+/// midpoint of the tile in pixel space, which lies poleward of the midpoint
+/// of its latitude range because the Mercator projection stretches
+/// latitudes toward the poles.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="latitude">Output parameter receiving the latitude in degrees.</param>
+/// <param name="longitude">Output parameter receiving the longitude in degrees.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func QuadKeyToCenter(quadKey string) (latitude float64, longitude float64, err error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	latitude, longitude = tileCenter(tileX, tileY, levelOfDetail)
+	return
+}
+
+/// <summary>
+/// Converts a QuadKey into the latitude/longitude bounds of its tile. The
+/// level 0 tile, the empty QuadKey, spans the full Mercator extent:
+/// latitudes MinLatitude to MaxLatitude and longitudes -180 to 180.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="minLat">Output parameter receiving the southern edge, in degrees.</param>
+/// <param name="minLong">Output parameter receiving the western edge, in degrees.</param>
+/// <param name="maxLat">Output parameter receiving the northern edge, in degrees.</param>
+/// <param name="maxLong">Output parameter receiving the eastern edge, in degrees.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func QuadKeyToBoundingBox(quadKey string) (minLat float64, minLong float64, maxLat float64, maxLong float64, err error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	bounds := tileBounds(tileX, tileY, levelOfDetail)
+	return bounds[0], bounds[1], bounds[2], bounds[3], nil
+}
+
+/// <summary>
+/// Snaps a point to the upper-left (northwest) corner of the tile that
+/// contains it, for aligning vector overlays to the tile grid. The tile is
+/// the one LatLongToQuadKey assigns, so a point exactly on a tile edge
+/// belongs to the tile east or south of the edge. The corner is nudged by
+/// the few units in the last place needed for it to lie in its own tile,
+/// so snapping a snapped point returns it unchanged.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="lat">Output parameter receiving the corner latitude, in degrees.</param>
+/// <param name="long">Output parameter receiving the corner longitude, in degrees.</param>
+func SnapToTile(latitude float64, longitude float64, levelOfDetail uint) (lat float64, long float64) {
+	pixelX, pixelY := LatLongToPixelXY(latitude, longitude, levelOfDetail)
+	tileX, tileY := PixelXYToTileXY(pixelX, pixelY)
+	bounds := tileBounds(tileX, tileY, levelOfDetail)
+	lat, long = bounds[2], bounds[1]
+	for {
+		cornerX, cornerY := PixelXYToTileXY(LatLongToPixelXY(lat, long, levelOfDetail))
+		if cornerY < tileY {
+			lat = math.Nextafter(lat, math.Inf(-1))
+		} else if cornerX < tileX {
+			long = math.Nextafter(long, math.Inf(1))
+		} else {
+			return lat, long
+		}
+	}
+}
+
+/// <summary>
+/// Determines the ground area of a tile on the spherical Earth model used
+/// by the projection (radius EarthRadius). Tiles shrink toward the poles,
+/// so tiles of one level differ in area by latitude.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The area in square meters, or 0 if the QuadKey is invalid.</returns>
+func QuadKeyArea(quadKey string) float64 {
+	if !validQuadKey(quadKey) {
+		return 0
+	}
+	tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+	bounds := tileBounds(tileX, tileY, levelOfDetail)
+	width := (bounds[3] - bounds[1]) * math.Pi / 180
+	height := math.Sin(bounds[2]*math.Pi/180) - math.Sin(bounds[0]*math.Pi/180)
+	return EarthRadius * EarthRadius * width * height
+}
+
+/// <summary>
+/// Generates a regular grid of sample points inside a tile, at the centers
+/// of cols x rows equal pixel-space cells. Every point lies strictly inside
+/// the tile footprint, which makes the grid suitable for probing a raster
+/// or checking point-in-tile logic reproducibly.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="cols">Number of sample columns.</param>
+/// <param name="rows">Number of sample rows.</param>
+/// <returns>The points as {latitude, longitude} pairs, row by row from north
+/// to south and west to east within a row, or nil if the QuadKey is
+/// invalid or cols or rows is less than 1.</returns>
+func SamplePointsInTile(quadKey string, cols int, rows int) [][2]float64 {
+	if !validQuadKey(quadKey) || cols < 1 || rows < 1 {
+		return nil
+	}
+	tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+	pixelX, pixelY := TileXYToPixelXY(tileX, tileY)
+
+	points := make([][2]float64, 0, cols*rows)
+	for row := 0; row < rows; row++ {
+		y := float64(pixelY) + (float64(row)+0.5)*TileSize/float64(rows)
+		for col := 0; col < cols; col++ {
+			x := float64(pixelX) + (float64(col)+0.5)*TileSize/float64(cols)
+			latitude, longitude := pixelXYToLatLongFloat(x, y, levelOfDetail)
+			points = append(points, [2]float64{latitude, longitude})
+		}
+	}
+	return points
+}
+
+/// <summary>
+/// Determines the convex hull of the corners of a set of tiles, a tight
+/// enclosing polygon for drawing an outline around an irregular region.
+/// The hull is computed with the monotone chain algorithm in pixel space,
+/// where tile edges are straight, so it is convex on the Mercator map
+/// rather than on the sphere, and it does not wrap across the antimeridian.
+/// Invalid keys are ignored.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles, of any mix of levels.</param>
+/// <returns>The hull vertices as {latitude, longitude} pairs in
+/// counter-clockwise order, without repeating the first vertex, or nil if
+/// there are no valid keys.</returns>
+func TileSetHull(keys []string) [][2]float64 {
+	var levelOfDetail uint
+	var valid []string
+	for _, quadKey := range keys {
+		if validQuadKey(quadKey) {
+			valid = append(valid, quadKey)
+			if uint(len(quadKey)) > levelOfDetail {
+				levelOfDetail = uint(len(quadKey))
+			}
+		}
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+
+	// Corners as (x, -y) so that counter-clockwise on the map is
+	// counter-clockwise in the hull's coordinate system.
+	var points [][2]float64
+	seen := make(map[[2]float64]bool)
+	for _, quadKey := range valid {
+		tileX, tileY, level := QuadKeyToTileXY(quadKey)
+		size := float64(int(TileSize) << (levelOfDetail - level))
+		for _, corner := range [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+			p := [2]float64{(float64(tileX) + corner[0]) * size, -(float64(tileY) + corner[1]) * size}
+			if !seen[p] {
+				seen[p] = true
+				points = append(points, p)
+			}
+		}
+	}
+	sort.Slice(points, func(i int, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] < points[j][0]
+		}
+		return points[i][1] < points[j][1]
+	})
+
+	cross := func(o [2]float64, a [2]float64, b [2]float64) float64 {
+		return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+	}
+	hull := make([][2]float64, 0, 2*len(points))
+	for _, p := range points {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	hull = hull[:len(hull)-1]
+
+	polygon := make([][2]float64, len(hull))
+	for i, p := range hull {
+		latitude, longitude := pixelXYToLatLongFloat(p[0], -p[1], levelOfDetail)
+		polygon[i] = [2]float64{latitude, longitude}
+	}
+	return polygon
+}
+
+/// <summary>
+/// Determines the mean latitude of a set of tiles, weighting each tile's
+/// center latitude by its ground area (see QuadKeyArea). Tiles of one level
+/// cover less ground toward the poles, so a plain mean of center latitudes
+/// over-weights polar tiles. Invalid keys are ignored.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles, of any mix of levels.</param>
+/// <returns>The weighted mean latitude in degrees, or 0 if there are no
+/// valid keys.</returns>
+func WeightedMeanLatitude(keys []string) float64 {
+	weightedSum, totalArea := 0.0, 0.0
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+		latitude, _ := tileCenter(tileX, tileY, levelOfDetail)
+		area := QuadKeyArea(quadKey)
+		weightedSum += latitude * area
+		totalArea += area
+	}
+	if totalArea == 0 {
+		return 0
+	}
+	return weightedSum / totalArea
+}
+
+/// <summary>
+/// Determines the ground resolution of every tile in a set, measured at
+/// each tile's center latitude and own level of detail with
+/// GroundResolution. Resolution depends on both: it halves with each level
+/// and shrinks with the cosine of the latitude, so tiles of one level
+/// differ toward the poles. Invalid keys are skipped.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles, of any mix of levels.</param>
+/// <returns>The resolutions in meters per pixel, keyed by QuadKey.</returns>
+func TileResolutions(keys []string) map[string]float64 {
+	resolutions := make(map[string]float64, len(keys))
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+		latitude, _ := tileCenter(tileX, tileY, levelOfDetail)
+		resolutions[quadKey] = GroundResolution(latitude, levelOfDetail)
+	}
+	return resolutions
+}
+
+/// <summary>
+/// Determines the centroid of a tile set, the plain mean of its tile
+/// centers. Invalid keys are ignored.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles.</param>
+/// <returns>The centroid as a {latitude, longitude} pair, and false if there
+/// are no valid keys.</returns>
+func tileSetCentroid(keys []string) ([2]float64, bool) {
+	var sum [2]float64
+	count := 0
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+		latitude, longitude := tileCenter(tileX, tileY, levelOfDetail)
+		sum[0] += latitude
+		sum[1] += longitude
+		count++
+	}
+	if count == 0 {
+		return sum, false
+	}
+	return [2]float64{sum[0] / float64(count), sum[1] / float64(count)}, true
+}
+
+/// <summary>
+/// Chooses the tile of a cluster on which to place its label so that labels
+/// of neighboring clusters spread apart. Each other cluster is reduced to
+/// its centroid, the mean of its tile centers, and a tile scores by the
+/// distance from its center to the nearest of those centroids, measured on
+/// the sphere (max-min selection). With no other clusters a tile scores by
+/// its nearness to the cluster's own centroid, its visual center, where
+/// each key's center is weighted by the number of tiles it spans at the
+/// requested level. Keys finer than the requested level collapse to their
+/// ancestors. A coarser key is not expanded into its descendants: it is
+/// descended one level at a time into whichever of its four children
+/// scores best, so at most four tiles are scored per level. The label is
+/// the best scoring of the resulting tiles; ties go to the smallest
+/// QuadKey. Centroids are not wrapped, so clusters should not cross the
+/// antimeridian.
+/// </summary>
+/// <param name="clusterKeys">QuadKeys of the cluster's tiles.</param>
+/// <param name="otherClusters">QuadKeys of the tiles of each other cluster.</param>
+/// <param name="levelOfDetail">Level of detail of the returned tile, from 1
+/// (lowest detail) to 23 (highest detail).</param>
+/// <returns>The QuadKey of the label tile, or an empty string if the
+/// cluster has no valid keys or the level is above MaxLevel.</returns>
+func LabelTile(clusterKeys []string, otherClusters [][]string, levelOfDetail uint) string {
+	if validLevel(levelOfDetail) != nil {
+		return ""
+	}
+	seen := make(map[string]bool)
+	var keys []string
+	for _, quadKey := range clusterKeys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		if uint(len(quadKey)) > levelOfDetail {
+			quadKey = quadKey[:levelOfDetail]
+		}
+		if !seen[quadKey] {
+			seen[quadKey] = true
+			keys = append(keys, quadKey)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	var centroids [][2]float64
+	for _, others := range otherClusters {
+		if centroid, ok := tileSetCentroid(others); ok {
+			centroids = append(centroids, centroid)
+		}
+	}
+	// Without other clusters, maximizing the negated distance to the
+	// cluster's own centroid picks its visual center.
+	sign := 1.0
+	if len(centroids) == 0 {
+		var centroid [2]float64
+		totalWeight := 0.0
+		for _, quadKey := range keys {
+			tileX, tileY, level := QuadKeyToTileXY(quadKey)
+			latitude, longitude := tileCenter(tileX, tileY, level)
+			weight := math.Ldexp(1, 2*int(levelOfDetail-level))
+			centroid[0] += weight * latitude
+			centroid[1] += weight * longitude
+			totalWeight += weight
+		}
+		centroids = append(centroids, [2]float64{centroid[0] / totalWeight, centroid[1] / totalWeight})
+		sign = -1
+	}
+	score := func(quadKey string) float64 {
+		tileX, tileY, level := QuadKeyToTileXY(quadKey)
+		latitude, longitude := tileCenter(tileX, tileY, level)
+		nearest := math.Inf(1)
+		for _, centroid := range centroids {
+			nearest = math.Min(nearest, HaversineMeters(latitude, longitude, centroid[0], centroid[1]))
+		}
+		return sign * nearest
+	}
+
+	best, bestScore := "", math.Inf(-1)
+	for _, quadKey := range keys {
+		for uint(len(quadKey)) < levelOfDetail {
+			child, childScore := "", math.Inf(-1)
+			for digit := byte('0'); digit <= '3'; digit++ {
+				candidate := quadKey + string(digit)
+				if candidateScore := score(candidate); candidateScore > childScore {
+					child, childScore = candidate, candidateScore
+				}
+			}
+			quadKey = child
+		}
+		if keyScore := score(quadKey); keyScore > bestScore || (keyScore == bestScore && quadKey < best) {
+			best, bestScore = quadKey, keyScore
+		}
+	}
+	return best
+}
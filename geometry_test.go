@@ -0,0 +1,110 @@
+// Quadkeys project geometry_test.go
+package Quadkeys
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSnapToTileCornerNotCenter(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		level := uint(1 + r.Intn(MaxLevel))
+		n := tilesPerSide(level)
+		quadKey := TileXYToQuadKey(r.Intn(n), r.Intn(n), level)
+		minLat, minLong, maxLat, maxLong, err := QuadKeyToBoundingBox(quadKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		centerLat, centerLong, err := QuadKeyToCenter(quadKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Any point inside the tile, its center included, snaps to the
+		// tile's northwest corner.
+		insideLat := minLat + (maxLat-minLat)*(0.1+0.8*r.Float64())
+		insideLong := minLong + (maxLong-minLong)*(0.1+0.8*r.Float64())
+		for _, point := range [][2]float64{{centerLat, centerLong}, {insideLat, insideLong}} {
+			lat, long := SnapToTile(point[0], point[1], level)
+			if math.Abs(lat-maxLat) > 1e-9 || math.Abs(long-minLong) > 1e-9 {
+				t.Fatalf("SnapToTile(%v, %v, %d) = %v, %v, want the corner %v, %v of %q", point[0], point[1], level, lat, long, maxLat, minLong, quadKey)
+			}
+			if lat == centerLat || long == centerLong {
+				t.Fatalf("SnapToTile(%v, %v, %d) = %v, %v is the center of %q, not its corner", point[0], point[1], level, lat, long, quadKey)
+			}
+		}
+	}
+}
+
+func TestSnapToTileBoundaries(t *testing.T) {
+	tests := []struct {
+		name                string
+		latitude, longitude float64
+		level               uint
+		lat, long           float64
+	}{
+		{"corner of all four level 1 tiles", 0, 0, 1, 0, 0},
+		{"prime meridian edge belongs east", 10, 0, 1, MaxLatitude, 0},
+		{"equator edge belongs south", 0, -100, 1, 0, -180},
+		{"equator and meridian at level 2", 0, -90, 2, 0, -90},
+		{"western map edge", -40, -180, 2, 0, -180},
+		{"eastern map edge clamps west", 80, 180, 2, MaxLatitude, 90},
+	}
+	for _, test := range tests {
+		lat, long := SnapToTile(test.latitude, test.longitude, test.level)
+		if math.Abs(lat-test.lat) > 1e-9 || math.Abs(long-test.long) > 1e-9 {
+			t.Errorf("%s: SnapToTile(%v, %v, %d) = %v, %v, want %v, %v", test.name, test.latitude, test.longitude, test.level, lat, long, test.lat, test.long)
+		}
+	}
+}
+
+func TestSnapToTileIdempotent(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 100000; i++ {
+		level := uint(1 + r.Intn(MaxLevel))
+		latitude := r.Float64()*2*MaxLatitude - MaxLatitude
+		longitude := r.Float64()*360 - 180
+		lat, long := SnapToTile(latitude, longitude, level)
+		if lat2, long2 := SnapToTile(lat, long, level); lat2 != lat || long2 != long {
+			t.Fatalf("level %d: corner %v, %v snapped again to %v, %v", level, lat, long, lat2, long2)
+		}
+	}
+}
+
+func TestLabelTile(t *testing.T) {
+	// A 4x1 row of level 10 tiles; with no other clusters the label goes
+	// to a middle tile, and with another cluster to the east, to the west end.
+	var row []string
+	for i := 0; i < 4; i++ {
+		row = append(row, TileXYToQuadKey(500+i, 300, 10))
+	}
+	if got := LabelTile(row, nil, 10); got != row[1] && got != row[2] {
+		t.Errorf("LabelTile without neighbors = %q, want a middle tile", got)
+	}
+	east := []string{TileXYToQuadKey(520, 300, 10)}
+	if got := LabelTile(row, [][]string{east}, 10); got != row[0] {
+		t.Errorf("LabelTile with an eastern neighbor = %q, want %q", got, row[0])
+	}
+	if got := LabelTile([]string{"x"}, nil, 10); got != "" {
+		t.Errorf("LabelTile of invalid keys = %q, want empty", got)
+	}
+}
+
+func TestLabelTileCoarseKeyDescends(t *testing.T) {
+	got := LabelTile([]string{"0"}, nil, 20)
+	if len(got) != 20 || got[0] != '0' {
+		t.Fatalf("LabelTile(\"0\", 20) = %q, want a level 20 tile under \"0\"", got)
+	}
+	// The center of quadrant "0" is the corner shared by level 20 tiles
+	// (2^18-1, 2^18-1) through (2^18, 2^18).
+	tileX, tileY, _ := QuadKeyToTileXY(got)
+	if half := 1 << 18; tileX < half-1 || tileX > half || tileY < half-1 || tileY > half {
+		t.Errorf("LabelTile(\"0\", 20) chose tile (%d, %d), far from the quadrant center", tileX, tileY)
+	}
+	west := [][]string{{TileXYToQuadKey(0, 1<<18, 20)}}
+	got = LabelTile([]string{"0"}, west, 20)
+	if tileX, _, _ := QuadKeyToTileXY(got); tileX < 1<<19-2 {
+		t.Errorf("LabelTile(\"0\") away from a western neighbor chose column %d, want the eastern edge", tileX)
+	}
+}
@@ -0,0 +1,244 @@
+// Quadkeys project hierarchy.go
+package Quadkeys
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+/// <summary>
+/// Determines the parent of a tile, the tile one level up that contains it:
+/// its QuadKey without the last digit.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The QuadKey of the parent tile, and an error if the QuadKey is
+/// invalid or is the level 0 tile, which has no parent.</returns>
+func Parent(quadKey string) (string, error) {
+	if _, _, _, err := QuadKeyToTileXYErr(quadKey); err != nil {
+		return "", err
+	}
+	if quadKey == "" {
+		return "", errors.New("level 0 tile has no parent")
+	}
+	return quadKey[:len(quadKey)-1], nil
+}
+
+/// <summary>
+/// Determines the four children of a tile, the tiles one level down that
+/// it contains. They are always in digit order, which in the Bing scheme is
+/// northwest ("0"), northeast ("1"), southwest ("2") and southeast ("3").
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The QuadKeys of the children, and an error if the QuadKey is
+/// invalid or already at MaxLevel.</returns>
+func Children(quadKey string) ([4]string, error) {
+	var children [4]string
+	if _, _, _, err := QuadKeyToTileXYErr(quadKey); err != nil {
+		return children, err
+	}
+	if len(quadKey) >= MaxLevel {
+		return children, errors.New("tile at the maximum level has no children")
+	}
+	for i := range children {
+		children[i] = quadKey + string(byte('0'+i))
+	}
+	return children, nil
+}
+
+/// <summary>
+/// The most tiles Descendants and GrayCodeQuadKeys will return.
+/// </summary>
+const MaxDescendants = 1 << 20
+
+/// <summary>
+/// Streams every descendant of a tile at a deeper level of detail to a
+/// callback, so large pyramids can be pre-rendered without holding all
+/// their keys. Descendants are visited depth-first, digit-ascending, which
+/// is also sorted order. Iteration stops at the first error the callback
+/// returns. There is no tile limit.
+/// </summary>
+/// <param name="parent">QuadKey of the tile.</param>
+/// <param name="targetLevel">Level of detail of the descendants, at least
+/// the tile's own level and at most MaxLevel; at the tile's own level the
+/// tile itself is visited.</param>
+/// <param name="fn">Function called with each descendant's QuadKey.</param>
+/// <returns>The error returned by fn, or an error if the arguments are invalid.</returns>
+func DescendantsFunc(parent string, targetLevel uint, fn func(quadKey string) error) error {
+	if _, _, _, err := QuadKeyToTileXYErr(parent); err != nil {
+		return err
+	}
+	if err := validLevel(targetLevel); err != nil {
+		return err
+	}
+	if targetLevel < uint(len(parent)) {
+		return fmt.Errorf("target level %d shallower than tile %q at level %d", targetLevel, parent, len(parent))
+	}
+	depth := targetLevel - uint(len(parent))
+	for i := uint64(0); i < uint64(1)<<(2*depth); i++ {
+		if err := fn(parent + zIndexToQuadKey(i, depth)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/// <summary>
+/// Determines every descendant of a tile at a deeper level of detail, the
+/// 4^(targetLevel - len(parent)) tiles it contains at that level, in the
+/// depth-first, digit-ascending order of DescendantsFunc.
+/// </summary>
+/// <param name="parent">QuadKey of the tile.</param>
+/// <param name="targetLevel">Level of detail of the descendants, at least
+/// the tile's own level and at most MaxLevel.</param>
+/// <returns>The QuadKeys of the descendants, and an error if the arguments
+/// are invalid or there would be more than MaxDescendants.</returns>
+func Descendants(parent string, targetLevel uint) ([]string, error) {
+	var keys []string
+	if targetLevel >= uint(len(parent)) && targetLevel <= MaxLevel {
+		count := uint64(1) << (2 * (targetLevel - uint(len(parent))))
+		if count > MaxDescendants {
+			return nil, fmt.Errorf("tile %q has %d descendants at level %d, more than %d", parent, count, targetLevel, MaxDescendants)
+		}
+		keys = make([]string, 0, count)
+	}
+	err := DescendantsFunc(parent, targetLevel, func(quadKey string) error {
+		keys = append(keys, quadKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+/// <summary>
+/// Determines whether a point falls within a tile. The point's unrounded
+/// pixel position is divided into tiles directly, so each tile owns the
+/// half-open ranges [min, max) in X and Y and a point exactly on an edge
+/// shared by two tiles belongs to the tile east or south of it. Points on
+/// the map's eastern and southern edges, and points outside the map, are
+/// clamped into the edge tiles. A NaN coordinate is in no tile.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <returns>True if the tile contains the point, and an error if the QuadKey
+/// is invalid.</returns>
+func Contains(quadKey string, latitude float64, longitude float64) (bool, error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return false, err
+	}
+	if math.IsNaN(latitude) || math.IsNaN(longitude) {
+		return false, nil
+	}
+	pixelX, pixelY := LatLongToPixelXYFloat(latitude, longitude, levelOfDetail)
+	lastTile := float64(tilesPerSide(levelOfDetail) - 1)
+	return int(clip(math.Floor(pixelX/TileSize), 0, lastTile)) == tileX &&
+		int(clip(math.Floor(pixelY/TileSize), 0, lastTile)) == tileY, nil
+}
+
+/// <summary>
+/// Determines the deepest tile containing two tiles: the longest common
+/// prefix of their QuadKeys. Tiles in different level 1 quadrants share
+/// only the level 0 world tile, the empty QuadKey.
+/// </summary>
+/// <param name="a">QuadKey of the first tile.</param>
+/// <param name="b">QuadKey of the second tile.</param>
+/// <returns>The QuadKey of the common ancestor, and an error if either
+/// QuadKey is invalid.</returns>
+func CommonAncestor(a string, b string) (string, error) {
+	if _, _, _, err := QuadKeyToTileXYErr(a); err != nil {
+		return "", err
+	}
+	if _, _, _, err := QuadKeyToTileXYErr(b); err != nil {
+		return "", err
+	}
+	return commonPrefix(a, b), nil
+}
+
+/// <summary>
+/// Determines whether one tile strictly contains another, as when a cached
+/// coarse tile already covers a requested finer one: the ancestor's
+/// QuadKey is a proper prefix of the descendant's. A tile is not its own
+/// ancestor.
+/// </summary>
+/// <param name="ancestor">QuadKey of the containing tile.</param>
+/// <param name="descendant">QuadKey of the contained tile.</param>
+/// <returns>True if both QuadKeys are valid and ancestor strictly contains
+/// descendant.</returns>
+func IsAncestor(ancestor string, descendant string) bool {
+	return validQuadKey(ancestor) && validQuadKey(descendant) &&
+		len(ancestor) < len(descendant) && strings.HasPrefix(descendant, ancestor)
+}
+
+/// <summary>
+/// Determines whether one tile is strictly contained by another; the
+/// mirror of IsAncestor.
+/// </summary>
+/// <param name="descendant">QuadKey of the contained tile.</param>
+/// <param name="ancestor">QuadKey of the containing tile.</param>
+/// <returns>True if both QuadKeys are valid and descendant is strictly
+/// contained by ancestor.</returns>
+func IsDescendant(descendant string, ancestor string) bool {
+	return IsAncestor(ancestor, descendant)
+}
+
+/// <summary>
+/// The quadrant of its parent a tile occupies, named by the QuadKey digit
+/// that selects it. The digit's low bit is the X bit and its high bit the
+/// Y bit, so 0 is northwest, 1 northeast, 2 southwest and 3 southeast.
+/// </summary>
+type Quadrant int
+
+const (
+	QuadrantNorthWest Quadrant = iota
+	QuadrantNorthEast
+	QuadrantSouthWest
+	QuadrantSouthEast
+)
+
+/// <summary>
+/// Converts the quadrant into its QuadKey digit.
+/// </summary>
+/// <returns>The digit, '0' to '3'.</returns>
+func (q Quadrant) Digit() byte {
+	return byte('0' + q)
+}
+
+/// <summary>
+/// Converts a QuadKey digit into the quadrant it selects.
+/// </summary>
+/// <param name="b">The digit, '0' to '3'.</param>
+/// <returns>The quadrant, and an error if b is not a QuadKey digit.</returns>
+func QuadrantFromDigit(b byte) (Quadrant, error) {
+	if b < '0' || b > '3' {
+		return 0, fmt.Errorf("invalid quadkey digit %q", b)
+	}
+	return Quadrant(b - '0'), nil
+}
+
+/// <summary>
+/// Expresses a tile at another level of detail. Zooming out truncates the
+/// QuadKey to the containing tile. Zooming in appends "0" digits, picking
+/// the northwest-most descendant: a coarse tile has no single finer tile,
+/// so use Descendants when every sub-tile is needed.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="targetLevel">Level of detail of the result, from 0 to 23.</param>
+/// <returns>The QuadKey at the target level, and an error if the QuadKey is
+/// invalid or the target level is above MaxLevel.</returns>
+func Rezoom(quadKey string, targetLevel uint) (string, error) {
+	if _, _, _, err := QuadKeyToTileXYErr(quadKey); err != nil {
+		return "", err
+	}
+	if err := validLevel(targetLevel); err != nil {
+		return "", err
+	}
+	if targetLevel <= uint(len(quadKey)) {
+		return quadKey[:targetLevel], nil
+	}
+	return quadKey + strings.Repeat("0", int(targetLevel)-len(quadKey)), nil
+}
@@ -0,0 +1,90 @@
+// Quadkeys project hierarchy_test.go
+package Quadkeys
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContainsTileBoundary(t *testing.T) {
+	// Lat 0, long 0 is the corner shared by all four level 1 tiles; it
+	// belongs to the south-east one.
+	for _, quadKey := range []string{"0", "1", "2", "3"} {
+		got, err := Contains(quadKey, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != (quadKey == "3") {
+			t.Errorf("Contains(%q, 0, 0) = %v", quadKey, got)
+		}
+	}
+
+	// The equator and the meridians at tile edges are exact in pixel space,
+	// so each grid corner on the equator belongs to the tile south-east of it.
+	for level := uint(1); level <= MaxLevel; level++ {
+		n := tilesPerSide(level)
+		for _, column := range []int{0, 1, n / 2, n - 1} {
+			longitude := -180 + 360*float64(column)/float64(n)
+			for dx := -1; dx <= 0; dx++ {
+				for dy := -1; dy <= 0; dy++ {
+					tileX, tileY := column+dx, n/2+dy
+					if tileX < 0 {
+						continue
+					}
+					got, err := Contains(TileXYToQuadKey(tileX, tileY, level), 0, longitude)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if want := dx == 0 && dy == 0; got != want {
+						t.Errorf("level %d: Contains(tile (%d, %d), 0, %v) = %v, want %v", level, tileX, tileY, longitude, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestContainsMapEdges(t *testing.T) {
+	tests := []struct {
+		quadKey             string
+		latitude, longitude float64
+	}{
+		{"1", 85, 180},
+		{"3", -MaxLatitude, 180},
+		{"2", -90, -180},
+		{"0", 90, -200},
+	}
+	for _, test := range tests {
+		if got, err := Contains(test.quadKey, test.latitude, test.longitude); err != nil || !got {
+			t.Errorf("Contains(%q, %v, %v) = %v, %v, want true", test.quadKey, test.latitude, test.longitude, got, err)
+		}
+	}
+	if got, _ := Contains("", math.NaN(), 0); got {
+		t.Error("Contains reported a NaN latitude inside the world tile")
+	}
+	if _, err := Contains("5", 0, 0); err == nil {
+		t.Error("Contains accepted an invalid QuadKey")
+	}
+}
+
+func TestCommonAncestor(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"0", "1", ""},
+		{"", "0123", ""},
+		{"0123", "0123", "0123"},
+		{"0123", "012", "012"},
+		{"02130", "02103", "021"},
+	}
+	for _, test := range tests {
+		if got, err := CommonAncestor(test.a, test.b); err != nil || got != test.want {
+			t.Errorf("CommonAncestor(%q, %q) = %q, %v, want %q", test.a, test.b, got, err, test.want)
+		}
+	}
+	for _, pair := range [][2]string{{"0", "4"}, {"x", "0"}, {"012", "01a"}} {
+		if got, err := CommonAncestor(pair[0], pair[1]); err == nil {
+			t.Errorf("CommonAncestor(%q, %q) = %q, want an error", pair[0], pair[1], got)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Quadkeys project latlong.go
+package Quadkeys
+
+/// <summary>
+/// A point as latitude/longitude WGS-84 coordinates, in degrees. Naming
+/// the two fields guards against swapping them at call sites.
+/// </summary>
+type LatLong struct {
+	Lat, Lng float64
+}
+
+/// <summary>
+/// Determines whether the point lies on the map: latitude within
+/// [MinLatitude, MaxLatitude] and longitude within [MinLongitude,
+/// MaxLongitude], the range LatLongToPixelXYStrict accepts. NaN is invalid.
+/// </summary>
+/// <returns>True if the point is on the map.</returns>
+func (p LatLong) Valid() bool {
+	return p.Lat >= MinLatitude && p.Lat <= MaxLatitude && p.Lng >= MinLongitude && p.Lng <= MaxLongitude
+}
+
+/// <summary>
+/// Converts the point into pixel XY coordinates at a specified level of
+/// detail, as LatLongToPixelXY does.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="pixelX">Output parameter receiving the X coordinate in pixels.</param>
+/// <param name="pixelY">Output parameter receiving the Y coordinate in pixels.</param>
+func (p LatLong) PixelXY(levelOfDetail uint) (pixelX int, pixelY int) {
+	return LatLongToPixelXY(p.Lat, p.Lng, levelOfDetail)
+}
+
+/// <summary>
+/// Converts the point into the QuadKey of its tile at a specified level of
+/// detail.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>A string containing the QuadKey.</returns>
+func (p LatLong) QuadKey(levelOfDetail uint) string {
+	return LatLongToQuadKey(p.Lat, p.Lng, levelOfDetail)
+}
+
+/// <summary>
+/// Determines the tile containing the point at a specified level of detail.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The tile.</returns>
+func (p LatLong) Tile(levelOfDetail uint) Tile {
+	tileX, tileY := PixelXYToTileXY(p.PixelXY(levelOfDetail))
+	return Tile{X: tileX, Y: tileY, Level: levelOfDetail}
+}
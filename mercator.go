@@ -0,0 +1,62 @@
+// Quadkeys project mercator.go
+package Quadkeys
+
+import (
+	"math"
+)
+
+/// <summary>
+/// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
+/// into spherical Mercator (EPSG:3857) coordinates in meters, for
+/// compositing with Web Mercator layers. The world spans
+/// +/-20037508.34 meters (pi * EarthRadius) on both axes. Latitude is
+/// clipped to [MinLatitude, MaxLatitude] as in LatLongToPixelXY.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="x">Output parameter receiving the easting, in meters.</param>
+/// <param name="y">Output parameter receiving the northing, in meters.</param>
+func LatLongToMeters(latitude float64, longitude float64) (x float64, y float64) {
+	latitude = clip(latitude, MinLatitude, MaxLatitude)
+	x = longitude * math.Pi / 180 * EarthRadius
+	y = math.Log(math.Tan(math.Pi/4+latitude*math.Pi/360)) * EarthRadius
+	return
+}
+
+/// <summary>
+/// Converts spherical Mercator (EPSG:3857) coordinates in meters into
+/// latitude/longitude WGS-84 coordinates (in degrees).
+/// </summary>
+/// <param name="x">Easting, in meters.</param>
+/// <param name="y">Northing, in meters.</param>
+/// <param name="latitude">Output parameter receiving the latitude in degrees.</param>
+/// <param name="longitude">Output parameter receiving the longitude in degrees.</param>
+func MetersToLatLong(x float64, y float64) (latitude float64, longitude float64) {
+	latitude = (2*math.Atan(math.Exp(y/EarthRadius)) - math.Pi/2) * 180 / math.Pi
+	longitude = x / EarthRadius * 180 / math.Pi
+	return
+}
+
+/// <summary>
+/// Determines the spherical Mercator (EPSG:3857) extent of a tile, as
+/// needed to set the geotransform of a rendered tile image. The corners
+/// follow the tile-to-pixel-to-latitude/longitude chain into
+/// LatLongToMeters; a corner shared by neighboring tiles comes from the
+/// same pixel in each, so adjacent extents meet exactly.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="minX">Output parameter receiving the western easting, in meters.</param>
+/// <param name="minY">Output parameter receiving the southern northing, in meters.</param>
+/// <param name="maxX">Output parameter receiving the eastern easting, in meters.</param>
+/// <param name="maxY">Output parameter receiving the northern northing, in meters.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func QuadKeyToMeterBounds(quadKey string) (minX float64, minY float64, maxX float64, maxY float64, err error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	bounds := tileBounds(tileX, tileY, levelOfDetail)
+	minX, minY = LatLongToMeters(bounds[0], bounds[1])
+	maxX, maxY = LatLongToMeters(bounds[2], bounds[3])
+	return
+}
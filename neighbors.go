@@ -0,0 +1,372 @@
+// Quadkeys project neighbors.go
+package Quadkeys
+
+import (
+	"sort"
+)
+
+/// <summary>
+/// Determines the tiles at a finer level of detail that lie directly across
+/// one edge of a coarser tile, as needed to stitch adaptive-resolution tile
+/// grids without cracks. East and west wrap around the antimeridian; north
+/// of the top row and south of the bottom row there are no tiles.
+/// </summary>
+/// <param name="quadKey">QuadKey of the coarse tile.</param>
+/// <param name="direction">Edge of the coarse tile: "north", "south",
+/// "east" or "west".</param>
+/// <param name="finerLevel">Level of detail of the returned tiles, deeper
+/// than the coarse tile and at most MaxLevel.</param>
+/// <returns>The QuadKeys of the 2^(finerLevel - level) adjacent tiles,
+/// ordered west to east or north to south, or nil if the arguments are
+/// invalid or the edge is at the top or bottom of the map.</returns>
+func CrossLevelNeighbors(quadKey string, direction string, finerLevel uint) []string {
+	tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+	if tileX < 0 || levelOfDetail > MaxLevel || finerLevel <= levelOfDetail || finerLevel > MaxLevel {
+		return nil
+	}
+
+	scale := 1 << (finerLevel - levelOfDetail)
+	tileCount := tilesPerSide(finerLevel)
+	minX, minY := tileX*scale, tileY*scale
+	maxX, maxY := minX+scale-1, minY+scale-1
+
+	var column, row int
+	vertical := false
+	switch direction {
+	case "north":
+		row = minY - 1
+	case "south":
+		row = maxY + 1
+	case "east":
+		column = (maxX + 1) % tileCount
+		vertical = true
+	case "west":
+		column = (minX - 1 + tileCount) % tileCount
+		vertical = true
+	default:
+		return nil
+	}
+	if !vertical && (row < 0 || row >= tileCount) {
+		return nil
+	}
+
+	keys := make([]string, 0, scale)
+	for i := 0; i < scale; i++ {
+		if vertical {
+			keys = append(keys, TileXYToQuadKey(column, minY+i, finerLevel))
+		} else {
+			keys = append(keys, TileXYToQuadKey(minX+i, row, finerLevel))
+		}
+	}
+	return keys
+}
+
+/// <summary>
+/// Determines the 4-connected neighbors of a tile, in north, east, south,
+/// west order. East and west wrap around the antimeridian; there is no
+/// neighbor north of the top row or south of the bottom row. A neighbor is
+/// listed once even when both wraps reach the same tile.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail of the tile.</param>
+/// <returns>The tile XY coordinates of the neighbors.</returns>
+func neighbors4(tileX int, tileY int, levelOfDetail uint) [][2]int {
+	tileCount := tilesPerSide(levelOfDetail)
+	candidates := [][2]int{
+		{tileX, tileY - 1},
+		{(tileX + 1) % tileCount, tileY},
+		{tileX, tileY + 1},
+		{(tileX - 1 + tileCount) % tileCount, tileY},
+	}
+	neighbors := make([][2]int, 0, 4)
+	for _, c := range candidates {
+		if c[1] < 0 || c[1] >= tileCount || (c[0] == tileX && c[1] == tileY) {
+			continue
+		}
+		duplicate := false
+		for _, n := range neighbors {
+			if n == c {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			neighbors = append(neighbors, c)
+		}
+	}
+	return neighbors
+}
+
+/// <summary>
+/// Builds the 4-connected adjacency graph of a tile set, ready for BFS or
+/// Dijkstra over tiles. Tiles are compared at their own level, so the
+/// graph is meaningful for a single-level set; only tiles present in the
+/// input appear as neighbors. Neighbors wrap east-west across the
+/// antimeridian but not north-south across the poles. Invalid keys are
+/// ignored.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles.</param>
+/// <returns>A map from each tile's QuadKey to the QuadKeys of its in-set
+/// neighbors, in north, east, south, west order.</returns>
+func AdjacencyGraph(keys []string) map[string][]string {
+	set := make(map[string]bool)
+	for _, quadKey := range keys {
+		if validQuadKey(quadKey) {
+			set[quadKey] = true
+		}
+	}
+
+	graph := make(map[string][]string, len(set))
+	for quadKey := range set {
+		tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+		adjacent := []string{}
+		for _, n := range neighbors4(tileX, tileY, levelOfDetail) {
+			neighbor := TileXYToQuadKey(n[0], n[1], levelOfDetail)
+			if set[neighbor] {
+				adjacent = append(adjacent, neighbor)
+			}
+		}
+		graph[quadKey] = adjacent
+	}
+	return graph
+}
+
+/// <summary>
+/// Finds a shortest 4-connected path between two tiles that stays within a
+/// set of walkable tiles, using a breadth-first search over AdjacencyGraph.
+/// All tiles are expected to share one level; paths may wrap across the
+/// antimeridian.
+/// </summary>
+/// <param name="mask">QuadKeys of the walkable tiles.</param>
+/// <param name="start">QuadKey of the start tile.</param>
+/// <param name="goal">QuadKey of the goal tile.</param>
+/// <returns>The QuadKeys along the path from start to goal inclusive, and
+/// false if either end is outside the mask or the goal is unreachable.</returns>
+func ShortestTilePath(mask []string, start string, goal string) ([]string, bool) {
+	graph := AdjacencyGraph(mask)
+	if _, ok := graph[start]; !ok {
+		return nil, false
+	}
+	if _, ok := graph[goal]; !ok {
+		return nil, false
+	}
+
+	previous := map[string]string{start: start}
+	queue := []string{start}
+	for len(queue) > 0 && queue[0] != goal {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[current] {
+			if _, visited := previous[next]; !visited {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	if _, reached := previous[goal]; !reached {
+		return nil, false
+	}
+
+	var path []string
+	for quadKey := goal; quadKey != start; quadKey = previous[quadKey] {
+		path = append(path, quadKey)
+	}
+	path = append(path, start)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, true
+}
+
+/// <summary>
+/// Determines the two tile columns that meet across the antimeridian: the
+/// westernmost column, starting at longitude -180, and the easternmost,
+/// ending at +180. Tiles at the same index in the two columns share an
+/// edge on the globe, which makes stitching across the date line easy.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="westColumn">Output parameter receiving the QuadKeys of the
+/// westernmost column, north to south.</param>
+/// <param name="eastColumn">Output parameter receiving the QuadKeys of the
+/// easternmost column, north to south.</param>
+func AntimeridianTiles(levelOfDetail uint) (westColumn []string, eastColumn []string) {
+	tileCount := tilesPerSide(levelOfDetail)
+	westColumn = make([]string, tileCount)
+	eastColumn = make([]string, tileCount)
+	for tileY := 0; tileY < tileCount; tileY++ {
+		westColumn[tileY] = TileXYToQuadKey(0, tileY, levelOfDetail)
+		eastColumn[tileY] = TileXYToQuadKey(tileCount-1, tileY, levelOfDetail)
+	}
+	return
+}
+
+/// <summary>
+/// Determines the deepest level of detail at which two points share a tile:
+/// the length of the common prefix of their QuadKeys at MaxLevel.
+/// </summary>
+/// <param name="lat1">Latitude of the first point, in degrees.</param>
+/// <param name="lon1">Longitude of the first point, in degrees.</param>
+/// <param name="lat2">Latitude of the second point, in degrees.</param>
+/// <param name="lon2">Longitude of the second point, in degrees.</param>
+/// <returns>The shared level of detail, from 0 to MaxLevel.</returns>
+func sharedLevel(lat1 float64, lon1 float64, lat2 float64, lon2 float64) uint {
+	return uint(len(commonPrefix(LatLongToQuadKey(lat1, lon1, MaxLevel), LatLongToQuadKey(lat2, lon2, MaxLevel))))
+}
+
+/// <summary>
+/// Determines the finest level of detail at which two points fall in
+/// 4-adjacent tiles, tiles sharing an edge (including across the
+/// antimeridian), which is the zoom at which two features visually touch.
+/// At and above the level where the points share a tile they cannot be
+/// adjacent, so only the deeper levels are searched, finest first. Going
+/// coarser, a pair can jump straight from distant or diagonal tiles into
+/// the same tile without ever being edge-adjacent; then there is no such
+/// level.
+/// </summary>
+/// <param name="lat1">Latitude of the first point, in degrees.</param>
+/// <param name="lon1">Longitude of the first point, in degrees.</param>
+/// <param name="lat2">Latitude of the second point, in degrees.</param>
+/// <param name="lon2">Longitude of the second point, in degrees.</param>
+/// <returns>The level of detail, and false if no level puts the points in
+/// 4-adjacent tiles.</returns>
+func AdjacencyLevel(lat1 float64, lon1 float64, lat2 float64, lon2 float64) (uint, bool) {
+	shared := sharedLevel(lat1, lon1, lat2, lon2)
+	for levelOfDetail := uint(MaxLevel); levelOfDetail > shared; levelOfDetail-- {
+		pixelX1, pixelY1 := LatLongToPixelXY(lat1, lon1, levelOfDetail)
+		pixelX2, pixelY2 := LatLongToPixelXY(lat2, lon2, levelOfDetail)
+		tileX1, tileY1 := PixelXYToTileXY(pixelX1, pixelY1)
+		tileX2, tileY2 := PixelXYToTileXY(pixelX2, pixelY2)
+		for _, n := range neighbors4(tileX1, tileY1, levelOfDetail) {
+			if n[0] == tileX2 && n[1] == tileY2 {
+				return levelOfDetail, true
+			}
+		}
+	}
+	return 0, false
+}
+
+/// <summary>
+/// Determines the seam between two neighboring tile sets: the tiles of each
+/// set that are 4-adjacent to a tile of the other, for stitching or
+/// blending along the shared boundary. Both sets are assumed to be at one
+/// common level; tiles are compared at their own level, so tiles at other
+/// levels never match. Adjacency wraps across the antimeridian. Invalid
+/// keys are ignored.
+/// </summary>
+/// <param name="a">QuadKeys of the first region's tiles.</param>
+/// <param name="b">QuadKeys of the second region's tiles.</param>
+/// <param name="aSide">Output parameter receiving the seam tiles of a, sorted.</param>
+/// <param name="bSide">Output parameter receiving the seam tiles of b, sorted.</param>
+func SeamTiles(a []string, b []string) (aSide []string, bSide []string) {
+	side := func(from []string, to []string) []string {
+		other := make(map[string]bool, len(to))
+		for _, quadKey := range to {
+			other[quadKey] = true
+		}
+		seen := make(map[string]bool)
+		var seam []string
+		for _, quadKey := range from {
+			if seen[quadKey] || !validQuadKey(quadKey) {
+				continue
+			}
+			seen[quadKey] = true
+			tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+			for _, n := range neighbors4(tileX, tileY, levelOfDetail) {
+				if other[TileXYToQuadKey(n[0], n[1], levelOfDetail)] {
+					seam = append(seam, quadKey)
+					break
+				}
+			}
+		}
+		sort.Strings(seam)
+		return seam
+	}
+	return side(a, b), side(b, a)
+}
+
+/// <summary>
+/// A compass direction from a tile to one of its eight neighbors.
+/// </summary>
+type Direction int
+
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+/// <summary>
+/// Determines the tile offset of a direction, with y growing southward.
+/// </summary>
+/// <param name="direction">The direction.</param>
+/// <param name="dx">Output parameter receiving the X offset.</param>
+/// <param name="dy">Output parameter receiving the Y offset.</param>
+/// <param name="ok">Output parameter receiving false for an unknown direction.</param>
+func (direction Direction) offset() (dx int, dy int, ok bool) {
+	switch direction {
+	case North:
+		return 0, -1, true
+	case NorthEast:
+		return 1, -1, true
+	case East:
+		return 1, 0, true
+	case SouthEast:
+		return 1, 1, true
+	case South:
+		return 0, 1, true
+	case SouthWest:
+		return -1, 1, true
+	case West:
+		return -1, 0, true
+	case NorthWest:
+		return -1, -1, true
+	}
+	return 0, 0, false
+}
+
+/// <summary>
+/// Determines the neighbor of the tile in a direction, for stitching
+/// adjacent tiles. East and west wrap around the antimeridian, the X
+/// coordinate taken modulo the number of tiles per side, so at level 0 the
+/// east and west neighbors are the tile itself. There is no neighbor north
+/// of the top row or south of the bottom row, so the northern directions
+/// from the top row and the southern directions from the bottom row report
+/// no tile rather than clamping.
+/// </summary>
+/// <param name="direction">Direction of the neighbor.</param>
+/// <returns>The neighboring tile, and false if there is none or the
+/// direction is unknown.</returns>
+func (t Tile) Neighbor(direction Direction) (Tile, bool) {
+	dx, dy, ok := direction.offset()
+	if !ok {
+		return Tile{}, false
+	}
+	tileCount := tilesPerSide(t.Level)
+	tileY := t.Y + dy
+	if tileY < 0 || tileY >= tileCount {
+		return Tile{}, false
+	}
+	return Tile{X: NormalizeTileX(t.X+dx, t.Level), Y: tileY, Level: t.Level}, true
+}
+
+/// <summary>
+/// Determines the up to eight tiles surrounding the tile, following the
+/// edge rules of Neighbor: wrapping east and west, and omitting directions
+/// beyond the top or bottom row.
+/// </summary>
+/// <returns>The neighboring tiles, keyed by direction.</returns>
+func (t Tile) Neighbors() map[Direction]Tile {
+	neighbors := make(map[Direction]Tile, 8)
+	for direction := North; direction <= NorthWest; direction++ {
+		if neighbor, ok := t.Neighbor(direction); ok {
+			neighbors[direction] = neighbor
+		}
+	}
+	return neighbors
+}
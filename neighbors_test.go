@@ -0,0 +1,49 @@
+// Quadkeys project neighbors_test.go
+package Quadkeys
+
+import (
+	"testing"
+)
+
+func TestAntimeridianTilesAdjacent(t *testing.T) {
+	for _, level := range []uint{1, 3, 8} {
+		west, east := AntimeridianTiles(level)
+		if len(west) != tilesPerSide(level) || len(east) != len(west) {
+			t.Fatalf("level %d: got %d west and %d east tiles, want %d each", level, len(west), len(east), tilesPerSide(level))
+		}
+		for i := range west {
+			wx, wy, _ := QuadKeyToTileXY(west[i])
+			ex, ey, _ := QuadKeyToTileXY(east[i])
+			westBounds := tileBounds(wx, wy, level)
+			eastBounds := tileBounds(ex, ey, level)
+			if westBounds[1] != -180 || eastBounds[3] != 180 {
+				t.Errorf("level %d row %d: west starts at %v and east ends at %v, want -180 and 180", level, i, westBounds[1], eastBounds[3])
+			}
+			if westBounds[0] != eastBounds[0] || westBounds[2] != eastBounds[2] {
+				t.Errorf("level %d row %d: latitude spans differ: %v vs %v", level, i, westBounds, eastBounds)
+			}
+			adjacent := false
+			for _, n := range neighbors4(ex, ey, level) {
+				if n == [2]int{wx, wy} {
+					adjacent = true
+				}
+			}
+			if !adjacent {
+				t.Errorf("level %d row %d: %s is not a neighbor of %s", level, i, west[i], east[i])
+			}
+		}
+	}
+}
+
+func TestAntimeridianTilesPointsAcross(t *testing.T) {
+	// Points just either side of the date line land in the paired tiles.
+	west, east := AntimeridianTiles(6)
+	for _, latitude := range []float64{-60, -1, 0.5, 45, 80} {
+		w := LatLongToQuadKey(latitude, -179.999, 6)
+		e := LatLongToQuadKey(latitude, 179.999, 6)
+		_, row, _ := QuadKeyToTileXY(w)
+		if west[row] != w || east[row] != e {
+			t.Errorf("latitude %v: got %s and %s, want %s and %s", latitude, w, e, west[row], east[row])
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Quadkeys project order.go
+package Quadkeys
+
+/// <summary>
+/// Streams every tile at a level of detail to a callback in Gray-code
+/// order, so that consecutive tiles differ in exactly one bit of one tile
+/// coordinate. The i-th tile is the one whose Z-order (Morton) index is
+/// i XOR (i >> 1); since a QuadKey is its tile's Z-order index written in
+/// base 4, each step changes a single X or Y bit. Iteration stops at the
+/// first error the callback returns. There is no tile limit.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 0 to MaxLevel.</param>
+/// <param name="fn">Function called with each tile's QuadKey.</param>
+/// <returns>The error returned by fn, or an error if the level is above MaxLevel.</returns>
+func GrayCodeQuadKeysFunc(levelOfDetail uint, fn func(quadKey string) error) error {
+	if err := validLevel(levelOfDetail); err != nil {
+		return err
+	}
+	count := uint64(1) << (2 * levelOfDetail)
+	for i := uint64(0); i < count; i++ {
+		if err := fn(zIndexToQuadKey(i^(i>>1), levelOfDetail)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/// <summary>
+/// Enumerates every tile at a level of detail in the Gray-code order of
+/// GrayCodeQuadKeysFunc. The result holds 4^levelOfDetail keys, so it is
+/// limited to MaxDescendants keys, level 10; use GrayCodeQuadKeysFunc to
+/// stream deeper levels.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 0 to MaxLevel.</param>
+/// <returns>The QuadKeys in Gray-code order, or nil if the level would
+/// yield more than MaxDescendants keys.</returns>
+func GrayCodeQuadKeys(levelOfDetail uint) []string {
+	if levelOfDetail > MaxLevel || uint64(1)<<(2*levelOfDetail) > MaxDescendants {
+		return nil
+	}
+	keys := make([]string, 0, uint64(1)<<(2*levelOfDetail))
+	GrayCodeQuadKeysFunc(levelOfDetail, func(quadKey string) error {
+		keys = append(keys, quadKey)
+		return nil
+	})
+	return keys
+}
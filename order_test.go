@@ -0,0 +1,59 @@
+// Quadkeys project order_test.go
+package Quadkeys
+
+import (
+	"errors"
+	"math/bits"
+	"testing"
+)
+
+func TestGrayCodeQuadKeysSingleStep(t *testing.T) {
+	for level := uint(0); level <= 6; level++ {
+		keys := GrayCodeQuadKeys(level)
+		if len(keys) != 1<<(2*level) {
+			t.Fatalf("level %d: got %d keys, want %d", level, len(keys), 1<<(2*level))
+		}
+		seen := make(map[string]bool, len(keys))
+		for i, quadKey := range keys {
+			if seen[quadKey] {
+				t.Fatalf("level %d: %q repeated", level, quadKey)
+			}
+			seen[quadKey] = true
+			if i == 0 {
+				continue
+			}
+			x0, y0, _ := QuadKeyToTileXY(keys[i-1])
+			x1, y1, _ := QuadKeyToTileXY(quadKey)
+			changed := bits.OnesCount(uint(x0^x1)) + bits.OnesCount(uint(y0^y1))
+			if changed != 1 {
+				t.Fatalf("level %d: %q -> %q changes %d bits, want 1", level, keys[i-1], quadKey, changed)
+			}
+		}
+	}
+}
+
+func TestGrayCodeQuadKeysLimit(t *testing.T) {
+	if keys := GrayCodeQuadKeys(10); len(keys) != MaxDescendants {
+		t.Errorf("GrayCodeQuadKeys(10) returned %d keys, want %d", len(keys), MaxDescendants)
+	}
+	for _, level := range []uint{11, MaxLevel, MaxLevel + 1} {
+		if keys := GrayCodeQuadKeys(level); keys != nil {
+			t.Errorf("GrayCodeQuadKeys(%d) returned %d keys, want nil", level, len(keys))
+		}
+	}
+}
+
+func TestGrayCodeQuadKeysFuncStops(t *testing.T) {
+	visited := 0
+	stop := errors.New("stop")
+	err := GrayCodeQuadKeysFunc(MaxLevel, func(quadKey string) error {
+		visited++
+		if visited == 5 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || visited != 5 {
+		t.Errorf("GrayCodeQuadKeysFunc stopped after %d keys with %v, want 5 and the callback error", visited, err)
+	}
+}
@@ -0,0 +1,261 @@
+// Quadkeys project polygon.go
+package Quadkeys
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	ringOutside = iota
+	ringPartial
+	ringInside
+)
+
+/// <summary>
+/// Projects a ring of latitude/longitude vertices into world coordinates,
+/// the Mercator map scaled to the unit square with (0, 0) at the top left.
+/// </summary>
+/// <param name="ring">Vertices as {latitude, longitude} pairs, in degrees.</param>
+/// <returns>The projected vertices as {x, y} pairs.</returns>
+func projectRing(ring [][2]float64) [][2]float64 {
+	projected := make([][2]float64, len(ring))
+	for i, vertex := range ring {
+		pixelX, pixelY := LatLongToPixelXYFloat(vertex[0], vertex[1], 0)
+		projected[i] = [2]float64{pixelX / TileSize, pixelY / TileSize}
+	}
+	return projected
+}
+
+/// <summary>
+/// Determines whether a point lies inside a ring, by even-odd ray casting.
+/// </summary>
+/// <param name="x">X coordinate of the point.</param>
+/// <param name="y">Y coordinate of the point.</param>
+/// <param name="ring">Projected vertices of the ring.</param>
+/// <returns>True if the point is inside the ring.</returns>
+func pointInRing(x float64, y float64, ring [][2]float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+/// <summary>
+/// Determines whether a segment touches an axis-aligned rectangle, by
+/// Liang-Barsky clipping.
+/// </summary>
+/// <param name="a">Start of the segment.</param>
+/// <param name="b">End of the segment.</param>
+/// <param name="rect">Rectangle as {minX, minY, maxX, maxY}.</param>
+/// <returns>True if any part of the segment lies in the rectangle.</returns>
+func segmentTouchesRect(a [2]float64, b [2]float64, rect [4]float64) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	// Each p*t <= q keeps the segment on the rectangle's side of one edge.
+	ps := [4]float64{-dx, dx, -dy, dy}
+	qs := [4]float64{a[0] - rect[0], rect[2] - a[0], a[1] - rect[1], rect[3] - a[1]}
+	for i := range ps {
+		if ps[i] == 0 {
+			if qs[i] < 0 {
+				return false
+			}
+			continue
+		}
+		t := qs[i] / ps[i]
+		if ps[i] < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+/// <summary>
+/// Classifies a tile against a ring as outside it, crossing its boundary,
+/// or wholly inside it. A tile the boundary merely touches counts as
+/// crossing.
+/// </summary>
+/// <param name="ring">Projected vertices of the ring.</param>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail of the tile.</param>
+/// <returns>ringOutside, ringPartial or ringInside.</returns>
+func ringTileRelation(ring [][2]float64, tileX int, tileY int, levelOfDetail uint) int {
+	size := 1 / float64(tilesPerSide(levelOfDetail))
+	rect := [4]float64{float64(tileX) * size, float64(tileY) * size, float64(tileX+1) * size, float64(tileY+1) * size}
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if segmentTouchesRect(ring[j], ring[i], rect) {
+			return ringPartial
+		}
+	}
+	if pointInRing((rect[0]+rect[2])/2, (rect[1]+rect[3])/2, ring) {
+		return ringInside
+	}
+	return ringOutside
+}
+
+/// <summary>
+/// Determines the tiles intersecting a polygon with holes, such as an
+/// administrative boundary with enclaves. Tiles are found by descending the
+/// quadtree from the world tile: tiles outside the outer ring or wholly
+/// inside a hole are pruned, tiles wholly inside the outer ring and clear
+/// of every hole are expanded to all their descendants, and the rest are
+/// split until the requested level. A tile straddling a hole's boundary is
+/// kept, since part of it lies in the polygon; only tiles entirely within
+/// a hole are excluded. Rings are straight in the Mercator projection, may
+/// be open or closed, and must not cross the antimeridian.
+/// </summary>
+/// <param name="outer">Vertices of the outer ring as {latitude, longitude} pairs.</param>
+/// <param name="holes">Vertices of each hole ring as {latitude, longitude} pairs.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the tiles, sorted, or nil if the outer ring has
+/// fewer than three vertices.</returns>
+func TilesForPolygonWithHoles(outer [][2]float64, holes [][][2]float64, levelOfDetail uint) []string {
+	if len(outer) < 3 {
+		return nil
+	}
+	outerRing := projectRing(outer)
+	var holeRings [][][2]float64
+	for _, hole := range holes {
+		if len(hole) >= 3 {
+			holeRings = append(holeRings, projectRing(hole))
+		}
+	}
+
+	var keys []string
+	var visit func(quadKey string)
+	visit = func(quadKey string) {
+		tileX, tileY, level := QuadKeyToTileXY(quadKey)
+		relation := ringTileRelation(outerRing, tileX, tileY, level)
+		if relation == ringOutside {
+			return
+		}
+		clearOfHoles := true
+		for _, hole := range holeRings {
+			switch ringTileRelation(hole, tileX, tileY, level) {
+			case ringInside:
+				return
+			case ringPartial:
+				clearOfHoles = false
+			}
+		}
+		if relation == ringInside && clearOfHoles {
+			keys = append(keys, descend(quadKey, levelOfDetail)...)
+			return
+		}
+		if level >= levelOfDetail {
+			keys = append(keys, quadKey)
+			return
+		}
+		for digit := byte('0'); digit <= '3'; digit++ {
+			visit(quadKey + string(digit))
+		}
+	}
+	visit("")
+	return keys
+}
+
+/// <summary>
+/// Determines the ground area enclosed by a projected ring on the spherical
+/// Earth model used by the projection. The area is the contour integral of
+/// sin(latitude) over longitude, with sin(latitude) = tanh(2 * pi * (0.5 -
+/// y)) in world coordinates; each edge, straight in the Mercator
+/// projection, is integrated by Simpson's rule.
+/// </summary>
+/// <param name="ring">Projected vertices of the ring.</param>
+/// <returns>The area in square meters.</returns>
+func ringArea(ring [][2]float64) float64 {
+	const steps = 16
+	sinLatitude := func(y float64) float64 {
+		return math.Tanh(2 * math.Pi * (0.5 - y))
+	}
+	sum := 0.0
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[j], ring[i]
+		integral := sinLatitude(a[1]) + sinLatitude(b[1])
+		for k := 1; k < steps; k++ {
+			weight := 2.0
+			if k%2 == 1 {
+				weight = 4
+			}
+			integral += weight * sinLatitude(a[1]+(b[1]-a[1])*float64(k)/steps)
+		}
+		sum += integral / (3 * steps) * (b[0] - a[0]) * 2 * math.Pi
+	}
+	return EarthRadius * EarthRadius * math.Abs(sum)
+}
+
+/// <summary>
+/// Determines a mixed-level tile covering of a polygon whose area is within
+/// a relative tolerance of the polygon's own, for accuracy-bounded rather
+/// than fixed-level coverage. Refinement starts from the world tile and
+/// proceeds a level at a time: tiles outside the ring are dropped, tiles
+/// wholly inside are kept at their level, and every boundary tile is split
+/// into its children. It stops once the covered area, the sum of
+/// QuadKeyArea over the kept and boundary tiles, exceeds the polygon area
+/// by at most toleranceFraction of it, or when the boundary tiles reach
+/// maxLevel. The polygon area is measured on the same sphere as
+/// QuadKeyArea, with edges straight in the Mercator projection. The ring may
+/// be open or closed and must not cross the antimeridian.
+/// </summary>
+/// <param name="ring">Vertices of the ring as {latitude, longitude} pairs.</param>
+/// <param name="toleranceFraction">Allowed excess of the covered area over
+/// the polygon area, as a fraction of the polygon area.</param>
+/// <param name="maxLevel">Deepest level of detail to refine to, at most MaxLevel.</param>
+/// <returns>The QuadKeys of the covering tiles, sorted, or nil if the ring
+/// has fewer than three vertices.</returns>
+func RefineToAreaTolerance(ring [][2]float64, toleranceFraction float64, maxLevel uint) []string {
+	if len(ring) < 3 {
+		return nil
+	}
+	if maxLevel > MaxLevel {
+		maxLevel = MaxLevel
+	}
+	projected := projectRing(ring)
+	targetArea := ringArea(projected)
+
+	var keys []string
+	var boundary []string
+	coveredArea := 0.0
+	classify := func(quadKey string) {
+		tileX, tileY, levelOfDetail := QuadKeyToTileXY(quadKey)
+		switch ringTileRelation(projected, tileX, tileY, levelOfDetail) {
+		case ringInside:
+			keys = append(keys, quadKey)
+		case ringPartial:
+			boundary = append(boundary, quadKey)
+		default:
+			return
+		}
+		coveredArea += QuadKeyArea(quadKey)
+	}
+
+	classify("")
+	for levelOfDetail := uint(0); levelOfDetail < maxLevel && len(boundary) > 0; levelOfDetail++ {
+		if coveredArea-targetArea <= toleranceFraction*targetArea {
+			break
+		}
+		splitting := boundary
+		boundary = nil
+		for _, quadKey := range splitting {
+			coveredArea -= QuadKeyArea(quadKey)
+			for digit := byte('0'); digit <= '3'; digit++ {
+				classify(quadKey + string(digit))
+			}
+		}
+	}
+
+	keys = append(keys, boundary...)
+	sort.Strings(keys)
+	return keys
+}
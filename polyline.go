@@ -0,0 +1,368 @@
+// Quadkeys project polyline.go
+package Quadkeys
+
+import (
+	"math"
+)
+
+/// <summary>
+/// Converts a fractional tile coordinate into the index of the tile that
+/// contains it, clamped to the tile grid.
+/// </summary>
+/// <param name="v">Fractional tile coordinate.</param>
+/// <param name="tileCount">Number of tiles along the axis.</param>
+/// <returns>The tile index.</returns>
+func tileIndex(v float64, tileCount int) int {
+	i := int(math.Floor(v))
+	if i < 0 {
+		return 0
+	}
+	if i > tileCount-1 {
+		return tileCount - 1
+	}
+	return i
+}
+
+/// <summary>
+/// Walks the tiles crossed by a straight segment between two fractional
+/// tile positions, in order from the start to the end. Every step moves to
+/// a 4-connected neighbor, so a segment passing exactly through a tile
+/// corner visits one of the two tiles sharing that corner.
+/// </summary>
+/// <param name="x0">Start X, in fractional tile coordinates.</param>
+/// <param name="y0">Start Y, in fractional tile coordinates.</param>
+/// <param name="x1">End X, in fractional tile coordinates.</param>
+/// <param name="y1">End Y, in fractional tile coordinates.</param>
+/// <param name="levelOfDetail">Level of detail of the tile grid.</param>
+/// <param name="visit">Called with the tile XY coordinates of each tile crossed.</param>
+func traverseTiles(x0 float64, y0 float64, x1 float64, y1 float64, levelOfDetail uint, visit func(tileX int, tileY int)) {
+	tileCount := tilesPerSide(levelOfDetail)
+	cx, cy := tileIndex(x0, tileCount), tileIndex(y0, tileCount)
+	ex, ey := tileIndex(x1, tileCount), tileIndex(y1, tileCount)
+
+	dx, dy := x1-x0, y1-y0
+	stepX, stepY := 1, 1
+	tMaxX, tMaxY := math.Inf(1), math.Inf(1)
+	tDeltaX, tDeltaY := math.Inf(1), math.Inf(1)
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+	if dx != 0 {
+		tDeltaX = math.Abs(1 / dx)
+		if dx > 0 {
+			tMaxX = (float64(cx+1) - x0) / dx
+		} else {
+			tMaxX = (float64(cx) - x0) / dx
+		}
+	}
+	if dy != 0 {
+		tDeltaY = math.Abs(1 / dy)
+		if dy > 0 {
+			tMaxY = (float64(cy+1) - y0) / dy
+		} else {
+			tMaxY = (float64(cy) - y0) / dy
+		}
+	}
+
+	visit(cx, cy)
+	for cx != ex || cy != ey {
+		// Once an axis has reached its end tile only the other axis may
+		// advance, which keeps rounding noise from overshooting the end.
+		if cy == ey || (cx != ex && tMaxX < tMaxY) {
+			cx += stepX
+			tMaxX += tDeltaX
+		} else {
+			cy += stepY
+			tMaxY += tDeltaY
+		}
+		visit(cx, cy)
+	}
+}
+
+/// <summary>
+/// Determines the tiles crossed by the straight line between two points at
+/// a specified level of detail. The line is straight in the Mercator
+/// projection (a rhumb line), not a great circle, and does not wrap across
+/// the antimeridian.
+/// </summary>
+/// <param name="latitude1">Latitude of the start point, in degrees.</param>
+/// <param name="longitude1">Longitude of the start point, in degrees.</param>
+/// <param name="latitude2">Latitude of the end point, in degrees.</param>
+/// <param name="longitude2">Longitude of the end point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the crossed tiles, ordered from start to end.</returns>
+func TilesAlongLine(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64, levelOfDetail uint) []string {
+	var keys []string
+	x0, y0 := LatLongToPixelXYFloat(latitude1, longitude1, levelOfDetail)
+	x1, y1 := LatLongToPixelXYFloat(latitude2, longitude2, levelOfDetail)
+	traverseTiles(x0/TileSize, y0/TileSize, x1/TileSize, y1/TileSize, levelOfDetail, func(tileX int, tileY int) {
+		keys = append(keys, TileXYToQuadKey(tileX, tileY, levelOfDetail))
+	})
+	return keys
+}
+
+/// <summary>
+/// Simplifies a polyline with the Douglas-Peucker algorithm. Distances are
+/// measured in meters on a local equirectangular projection centered on the
+/// mean latitude of the polyline, which is accurate for tracks spanning a
+/// few hundred kilometers.
+/// </summary>
+/// <param name="lats">Latitudes of the vertices, in degrees.</param>
+/// <param name="lons">Longitudes of the vertices, in degrees.</param>
+/// <param name="toleranceMeters">Maximum distance a dropped vertex may lie
+/// from the simplified polyline.</param>
+/// <returns>The latitudes and longitudes of the retained vertices.</returns>
+func simplifyPolyline(lats []float64, lons []float64, toleranceMeters float64) ([]float64, []float64) {
+	n := len(lats)
+	if n < 3 || toleranceMeters <= 0 {
+		return lats, lons
+	}
+
+	meanLatitude := 0.0
+	for _, lat := range lats {
+		meanLatitude += lat
+	}
+	meanLatitude /= float64(n)
+	scaleX := EarthRadius * math.Pi / 180 * math.Cos(meanLatitude*math.Pi/180)
+	scaleY := EarthRadius * math.Pi / 180
+
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i := range lats {
+		xs[i] = lons[i] * scaleX
+		ys[i] = lats[i] * scaleY
+	}
+
+	keep := make([]bool, n)
+	keep[0] = true
+	keep[n-1] = true
+	stack := [][2]int{{0, n - 1}}
+	for len(stack) > 0 {
+		span := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		first, last := span[0], span[1]
+
+		maxDistance := 0.0
+		index := -1
+		for i := first + 1; i < last; i++ {
+			d := segmentDistance(xs[i], ys[i], xs[first], ys[first], xs[last], ys[last])
+			if d > maxDistance {
+				maxDistance = d
+				index = i
+			}
+		}
+		if index >= 0 && maxDistance > toleranceMeters {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+
+	var outLats, outLons []float64
+	for i := range lats {
+		if keep[i] {
+			outLats = append(outLats, lats[i])
+			outLons = append(outLons, lons[i])
+		}
+	}
+	return outLats, outLons
+}
+
+/// <summary>
+/// Determines the point of a segment closest to a given point, in the plane.
+/// </summary>
+/// <param name="p">The point.</param>
+/// <param name="a">Start of the segment.</param>
+/// <param name="b">End of the segment.</param>
+/// <returns>The closest point of the segment.</returns>
+func closestOnSegment(p [2]float64, a [2]float64, b [2]float64) [2]float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	lengthSquared := dx*dx + dy*dy
+	t := 0.0
+	if lengthSquared > 0 {
+		t = clip(((p[0]-a[0])*dx+(p[1]-a[1])*dy)/lengthSquared, 0, 1)
+	}
+	return [2]float64{a[0] + t*dx, a[1] + t*dy}
+}
+
+/// <summary>
+/// Determines the planar distance from a point to a segment.
+/// </summary>
+/// <param name="px">X coordinate of the point.</param>
+/// <param name="py">Y coordinate of the point.</param>
+/// <param name="ax">X coordinate of the segment start.</param>
+/// <param name="ay">Y coordinate of the segment start.</param>
+/// <param name="bx">X coordinate of the segment end.</param>
+/// <param name="by">Y coordinate of the segment end.</param>
+/// <returns>The distance from the point to the closest point of the segment.</returns>
+func segmentDistance(px float64, py float64, ax float64, ay float64, bx float64, by float64) float64 {
+	c := closestOnSegment([2]float64{px, py}, [2]float64{ax, ay}, [2]float64{bx, by})
+	return math.Hypot(px-c[0], py-c[1])
+}
+
+/// <summary>
+/// Determines the closest pair of points between two segments, in the
+/// plane. Crossing segments meet at their intersection.
+/// </summary>
+/// <param name="a">Start of the first segment.</param>
+/// <param name="b">End of the first segment.</param>
+/// <param name="c">Start of the second segment.</param>
+/// <param name="d">End of the second segment.</param>
+/// <returns>The closest point on each segment.</returns>
+func closestBetweenSegments(a [2]float64, b [2]float64, c [2]float64, d [2]float64) ([2]float64, [2]float64) {
+	r := [2]float64{b[0] - a[0], b[1] - a[1]}
+	s := [2]float64{d[0] - c[0], d[1] - c[1]}
+	denominator := r[0]*s[1] - r[1]*s[0]
+	if denominator != 0 {
+		t := ((c[0]-a[0])*s[1] - (c[1]-a[1])*s[0]) / denominator
+		u := ((c[0]-a[0])*r[1] - (c[1]-a[1])*r[0]) / denominator
+		if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+			p := [2]float64{a[0] + t*r[0], a[1] + t*r[1]}
+			return p, p
+		}
+	}
+
+	// Otherwise an endpoint of one segment is involved.
+	candidates := [][2][2]float64{
+		{a, closestOnSegment(a, c, d)},
+		{b, closestOnSegment(b, c, d)},
+		{closestOnSegment(c, a, b), c},
+		{closestOnSegment(d, a, b), d},
+	}
+	best := candidates[0]
+	for _, pair := range candidates[1:] {
+		if math.Hypot(pair[0][0]-pair[1][0], pair[0][1]-pair[1][1]) < math.Hypot(best[0][0]-best[1][0], best[0][1]-best[1][1]) {
+			best = pair
+		}
+	}
+	return best[0], best[1]
+}
+
+/// <summary>
+/// Determines the tiles crossed by a polyline at a specified level of
+/// detail, after simplifying the polyline with the Douglas-Peucker
+/// algorithm. Simplification keeps the tile list compact for dense GPS
+/// tracks, but because dropped vertices may lie up to simplifyMeters away
+/// from the simplified line, tiles that the raw track only clipped can be
+/// missing from the result. Pass 0 to traverse the raw track.
+/// </summary>
+/// <param name="lats">Latitudes of the vertices, in degrees.</param>
+/// <param name="lons">Longitudes of the vertices, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="simplifyMeters">Douglas-Peucker tolerance, in meters.</param>
+/// <returns>The QuadKeys of the crossed tiles in the order they are first
+/// entered, without duplicates, or nil if lats and lons differ in length.</returns>
+func TilesAlongPolyline(lats []float64, lons []float64, levelOfDetail uint, simplifyMeters float64) []string {
+	if len(lats) != len(lons) || len(lats) == 0 {
+		return nil
+	}
+	lats, lons = simplifyPolyline(lats, lons, simplifyMeters)
+
+	var keys []string
+	seen := make(map[string]bool)
+	add := func(quadKey string) {
+		if !seen[quadKey] {
+			seen[quadKey] = true
+			keys = append(keys, quadKey)
+		}
+	}
+	if len(lats) == 1 {
+		add(LatLongToQuadKey(lats[0], lons[0], levelOfDetail))
+		return keys
+	}
+	for i := 1; i < len(lats); i++ {
+		for _, quadKey := range TilesAlongLine(lats[i-1], lons[i-1], lats[i], lons[i], levelOfDetail) {
+			add(quadKey)
+		}
+	}
+	return keys
+}
+
+/// <summary>
+/// Determines the tiles crossed by a polyline at the finest uniform level
+/// of detail whose tile count stays within a budget, bounding the output
+/// for arbitrarily long or detailed routes while keeping as much
+/// resolution as possible. The level is found by binary search over 0 to
+/// MaxLevel, running TilesAlongPolyline without simplification at each
+/// probe. Tile counts grow with the level, so the search settles on the
+/// finest level that fits; when counts plateau, the finer level wins.
+/// </summary>
+/// <param name="lats">Latitudes of the vertices, in degrees.</param>
+/// <param name="lons">Longitudes of the vertices, in degrees.</param>
+/// <param name="maxTiles">Maximum number of tiles.</param>
+/// <returns>The QuadKeys of the crossed tiles and their level of detail, or
+/// nil and 0 if maxTiles is less than 1 or lats and lons are empty or
+/// differ in length.</returns>
+func AdaptiveTilesAlongPolyline(lats []float64, lons []float64, maxTiles int) ([]string, uint) {
+	if maxTiles < 1 || len(lats) != len(lons) || len(lats) == 0 {
+		return nil, 0
+	}
+	best, bestLevel := TilesAlongPolyline(lats, lons, 0, 0), uint(0)
+	low, high := uint(1), uint(MaxLevel)
+	for low <= high {
+		mid := (low + high) / 2
+		keys := TilesAlongPolyline(lats, lons, mid, 0)
+		if len(keys) <= maxTiles {
+			best, bestLevel = keys, mid
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return best, bestLevel
+}
+
+/// <summary>
+/// Determines where two tracks come closest, for proximity and encounter
+/// alerts. Every pair of segments is compared in pixel space, treating
+/// segments as straight lines on the Mercator map (a planar approximation
+/// that is accurate for short segments away from the poles). The returned
+/// tile is the one containing the midpoint of the closest pair of points at
+/// the requested level, and the distance between that pair is measured on
+/// the sphere. A track with a single point is treated as that point.
+/// </summary>
+/// <param name="latsA">Latitudes of the first track, in degrees.</param>
+/// <param name="lonsA">Longitudes of the first track, in degrees.</param>
+/// <param name="latsB">Latitudes of the second track, in degrees.</param>
+/// <param name="lonsB">Longitudes of the second track, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="quadKey">Output parameter receiving the QuadKey of the closest approach.</param>
+/// <param name="distanceMeters">Output parameter receiving the distance at
+/// the closest approach, in meters.</param>
+func ClosestApproachTile(latsA []float64, lonsA []float64, latsB []float64, lonsB []float64, levelOfDetail uint) (quadKey string, distanceMeters float64) {
+	if len(latsA) != len(lonsA) || len(latsB) != len(lonsB) || len(latsA) == 0 || len(latsB) == 0 {
+		return "", 0
+	}
+	project := func(lats []float64, lons []float64) [][2]float64 {
+		points := make([][2]float64, len(lats))
+		for i := range lats {
+			points[i][0], points[i][1] = LatLongToPixelXYFloat(lats[i], lons[i], levelOfDetail)
+		}
+		if len(points) == 1 {
+			points = append(points, points[0])
+		}
+		return points
+	}
+	trackA, trackB := project(latsA, lonsA), project(latsB, lonsB)
+
+	var bestA, bestB [2]float64
+	bestDistance := math.Inf(1)
+	for i := 1; i < len(trackA); i++ {
+		for j := 1; j < len(trackB); j++ {
+			p, q := closestBetweenSegments(trackA[i-1], trackA[i], trackB[j-1], trackB[j])
+			if d := math.Hypot(p[0]-q[0], p[1]-q[1]); d < bestDistance {
+				bestA, bestB, bestDistance = p, q, d
+			}
+		}
+	}
+
+	latA, lonA := pixelXYToLatLongFloat(bestA[0], bestA[1], levelOfDetail)
+	latB, lonB := pixelXYToLatLongFloat(bestB[0], bestB[1], levelOfDetail)
+	midLat, midLon := pixelXYToLatLongFloat((bestA[0]+bestB[0])/2, (bestA[1]+bestB[1])/2, levelOfDetail)
+	return LatLongToQuadKey(midLat, midLon, levelOfDetail), HaversineMeters(latA, lonA, latB, lonB)
+}
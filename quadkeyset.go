@@ -0,0 +1,128 @@
+// Quadkeys project quadkeyset.go
+package Quadkeys
+
+/// <summary>
+/// A node of a QuadKeySet trie: one child per digit, and whether the
+/// QuadKey ending here is in the set.
+/// </summary>
+type quadKeyNode struct {
+	children [4]*quadKeyNode
+	present  bool
+}
+
+/// <summary>
+/// A set of QuadKeys stored as a trie on their digits, which answers
+/// "is this tile, or a coarser tile covering it, in the set?" in time
+/// proportional to the QuadKey's length. The zero value is an empty set
+/// ready to use. A QuadKeySet is not safe for concurrent use.
+/// </summary>
+type QuadKeySet struct {
+	root  quadKeyNode
+	count int
+}
+
+/// <summary>
+/// Adds a QuadKey to the set. Invalid QuadKeys are ignored.
+/// </summary>
+/// <param name="quadKey">The QuadKey to add.</param>
+/// <returns>True if the QuadKey was added, false if it was already present
+/// or is invalid.</returns>
+func (s *QuadKeySet) Add(quadKey string) bool {
+	if !validQuadKey(quadKey) {
+		return false
+	}
+	node := &s.root
+	for i := 0; i < len(quadKey); i++ {
+		digit := quadKey[i] - '0'
+		if node.children[digit] == nil {
+			node.children[digit] = &quadKeyNode{}
+		}
+		node = node.children[digit]
+	}
+	if node.present {
+		return false
+	}
+	node.present = true
+	s.count++
+	return true
+}
+
+/// <summary>
+/// Determines whether the set holds a QuadKey.
+/// </summary>
+/// <param name="quadKey">The QuadKey to look up.</param>
+/// <returns>True if the QuadKey is in the set.</returns>
+func (s *QuadKeySet) Contains(quadKey string) bool {
+	if !validQuadKey(quadKey) {
+		return false
+	}
+	node := &s.root
+	for i := 0; i < len(quadKey) && node != nil; i++ {
+		node = node.children[quadKey[i]-'0']
+	}
+	return node != nil && node.present
+}
+
+/// <summary>
+/// Determines whether the set holds a tile covering a QuadKey's tile: the
+/// QuadKey itself or any of its ancestors, so a prefix of it.
+/// </summary>
+/// <param name="quadKey">The QuadKey to look up.</param>
+/// <returns>True if the QuadKey or an ancestor is in the set.</returns>
+func (s *QuadKeySet) ContainsAncestor(quadKey string) bool {
+	if !validQuadKey(quadKey) {
+		return false
+	}
+	node := &s.root
+	for i := 0; ; i++ {
+		if node.present {
+			return true
+		}
+		if i == len(quadKey) {
+			return false
+		}
+		if node = node.children[quadKey[i]-'0']; node == nil {
+			return false
+		}
+	}
+}
+
+/// <summary>
+/// Determines the number of QuadKeys in the set.
+/// </summary>
+/// <returns>The number of QuadKeys.</returns>
+func (s *QuadKeySet) Len() int {
+	return s.count
+}
+
+/// <summary>
+/// Visits every QuadKey in the set in sorted order, which for a trie is
+/// depth-first, digit-ascending, each tile before its descendants.
+/// </summary>
+/// <param name="fn">Function called with each QuadKey.</param>
+func (s *QuadKeySet) Each(fn func(quadKey string)) {
+	var walk func(node *quadKeyNode, prefix []byte)
+	walk = func(node *quadKeyNode, prefix []byte) {
+		if node.present {
+			fn(string(prefix))
+		}
+		for digit, child := range node.children {
+			if child != nil {
+				walk(child, append(prefix, byte('0'+digit)))
+			}
+		}
+	}
+	walk(&s.root, make([]byte, 0, MaxLevel))
+}
+
+/// <summary>
+/// Determines the QuadKeys in the set.
+/// </summary>
+/// <returns>The QuadKeys, sorted.</returns>
+func (s *QuadKeySet) Keys() []string {
+	keys := make([]string, 0, s.count)
+	s.Each(func(quadKey string) {
+		keys = append(keys, quadKey)
+	})
+	return keys
+}
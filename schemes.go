@@ -0,0 +1,175 @@
+// Quadkeys project schemes.go
+package Quadkeys
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/// <summary>
+/// Converts tile XY coordinates into slippy-map (XYZ) tile coordinates, as
+/// used by OpenStreetMap and Leaflet tile servers in z/x/y URLs. Both
+/// schemes number columns from the west and rows from the north, so the
+/// conversion is a relabeling: z is the level of detail and x and y are the
+/// tile coordinates unchanged.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="z">Output parameter receiving the slippy zoom.</param>
+/// <param name="x">Output parameter receiving the slippy X coordinate.</param>
+/// <param name="y">Output parameter receiving the slippy Y coordinate.</param>
+func TileXYToSlippy(tileX int, tileY int, levelOfDetail uint) (z int, x int, y int) {
+	return int(levelOfDetail), tileX, tileY
+}
+
+/// <summary>
+/// Converts slippy-map (XYZ) tile coordinates into a QuadKey. Slippy y
+/// counts rows from the north, as tile Y does, so no flip is applied.
+/// </summary>
+/// <param name="z">Slippy zoom, from 0 to 23.</param>
+/// <param name="x">Slippy X coordinate.</param>
+/// <param name="y">Slippy Y coordinate.</param>
+/// <returns>A string containing the QuadKey, and an error if the zoom or
+/// coordinates are outside the tile system.</returns>
+func SlippyToQuadKey(z int, x int, y int) (string, error) {
+	if z < 0 {
+		return "", fmt.Errorf("negative zoom %d", z)
+	}
+	if err := validLevel(uint(z)); err != nil {
+		return "", err
+	}
+	return TileXYToQuadKeyErr(x, y, uint(z))
+}
+
+/// <summary>
+/// Converts a QuadKey into TMS tile coordinates, as used by GDAL-generated
+/// tile directories. TMS numbers rows from the south, so the row is
+/// flipped: y = (2^z - 1) - tileY.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="z">Output parameter receiving the TMS zoom.</param>
+/// <param name="x">Output parameter receiving the TMS X coordinate.</param>
+/// <param name="y">Output parameter receiving the TMS Y coordinate.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func QuadKeyToTMS(quadKey string) (z int, x int, y int, err error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return int(levelOfDetail), tileX, tilesPerSide(levelOfDetail) - 1 - tileY, nil
+}
+
+/// <summary>
+/// Converts TMS tile coordinates into a QuadKey, flipping the row back to
+/// count from the north: tileY = (2^z - 1) - y.
+/// </summary>
+/// <param name="z">TMS zoom, from 0 to 23.</param>
+/// <param name="x">TMS X coordinate.</param>
+/// <param name="y">TMS Y coordinate, counted from the south.</param>
+/// <returns>A string containing the QuadKey, and an error if the zoom or
+/// coordinates are outside the tile system.</returns>
+func TMSToQuadKey(z int, x int, y int) (string, error) {
+	if z < 0 {
+		return "", fmt.Errorf("negative zoom %d", z)
+	}
+	if err := validLevel(uint(z)); err != nil {
+		return "", err
+	}
+	return TileXYToQuadKeyErr(x, tilesPerSide(uint(z))-1-y, uint(z))
+}
+
+// googleQuadtreeLetters are the Google quadtree letters for digits 0 to 3.
+const googleQuadtreeLetters = "qrts"
+
+/// <summary>
+/// Converts a QuadKey into the quadtree letters of legacy Google Maps tile
+/// URLs, remapping each digit: 0 to q, 1 to r, 2 to t and 3 to s.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The Google quadtree string, and an error if the QuadKey is invalid.</returns>
+func QuadKeyToGoogleQuadtree(quadKey string) (string, error) {
+	if _, _, _, err := QuadKeyToTileXYErr(quadKey); err != nil {
+		return "", err
+	}
+	letters := make([]byte, len(quadKey))
+	for i := 0; i < len(quadKey); i++ {
+		letters[i] = googleQuadtreeLetters[quadKey[i]-'0']
+	}
+	return string(letters), nil
+}
+
+/// <summary>
+/// Converts the quadtree letters of a legacy Google Maps tile URL into a
+/// QuadKey, remapping q to 0, r to 1, t to 2 and s to 3. Google URLs often
+/// lead with a "t" naming the whole-world root; strip it first, since here
+/// it would be read as a level 1 digit.
+/// </summary>
+/// <param name="s">The Google quadtree string.</param>
+/// <returns>The QuadKey, and an error if s is longer than MaxLevel or has a
+/// letter other than q, r, s or t.</returns>
+func GoogleQuadtreeToQuadKey(s string) (string, error) {
+	if len(s) > MaxLevel {
+		return "", fmt.Errorf("quadtree has %d letters, more than the maximum level %d", len(s), MaxLevel)
+	}
+	digits := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(googleQuadtreeLetters, s[i])
+		if digit < 0 {
+			return "", fmt.Errorf("invalid quadtree letter %q at index %d", s[i], i)
+		}
+		digits[i] = byte('0' + digit)
+	}
+	return string(digits), nil
+}
+
+/// <summary>
+/// Parses a slippy-map tile path such as "14/2612/6333.png", as used by
+/// tile caches on disk and tile server URLs. A leading slash and the file
+/// extension are optional.
+/// </summary>
+/// <param name="path">The tile path, "z/x/y" or "z/x/y.ext".</param>
+/// <param name="z">Output parameter receiving the zoom.</param>
+/// <param name="x">Output parameter receiving the X coordinate.</param>
+/// <param name="y">Output parameter receiving the Y coordinate.</param>
+/// <param name="ext">Output parameter receiving the extension without its
+/// dot, or an empty string if there is none.</param>
+/// <param name="err">Output parameter receiving an error if the path does
+/// not have three parts or a part is not a non-negative integer.</param>
+func ParseTilePath(path string) (z int, x int, y int, ext string, err error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, "", fmt.Errorf("tile path %q is not of the form z/x/y", path)
+	}
+	if dot := strings.IndexByte(parts[2], '.'); dot >= 0 {
+		parts[2], ext = parts[2][:dot], parts[2][dot+1:]
+	}
+	var values [3]int
+	for i, part := range parts {
+		value, convErr := strconv.Atoi(part)
+		if convErr != nil || value < 0 {
+			return 0, 0, 0, "", fmt.Errorf("tile path %q has invalid %c coordinate %q", path, "zxy"[i], part)
+		}
+		values[i] = value
+	}
+	return values[0], values[1], values[2], ext, nil
+}
+
+/// <summary>
+/// Formats a slippy-map tile path, the inverse of ParseTilePath.
+/// </summary>
+/// <param name="z">The zoom.</param>
+/// <param name="x">The X coordinate.</param>
+/// <param name="y">The Y coordinate.</param>
+/// <param name="ext">The extension, with or without its dot, or an empty
+/// string for none.</param>
+/// <returns>The path as "z/x/y" or "z/x/y.ext".</returns>
+func FormatTilePath(z int, x int, y int, ext string) string {
+	path := strconv.Itoa(z) + "/" + strconv.Itoa(x) + "/" + strconv.Itoa(y)
+	if ext = strings.TrimPrefix(ext, "."); ext != "" {
+		path += "." + ext
+	}
+	return path
+}
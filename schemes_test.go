@@ -0,0 +1,85 @@
+// Quadkeys project schemes_test.go
+package Quadkeys
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestQuadKeyToTMSKnownValues(t *testing.T) {
+	tests := []struct {
+		quadKey string
+		z, x, y int
+	}{
+		{"", 0, 0, 0},
+		{"0", 1, 0, 1},
+		{"1", 1, 1, 1},
+		{"2", 1, 0, 0},
+		{"3", 1, 1, 0},
+		{"120", 3, 4, 5},
+		{"333", 3, 7, 0},
+	}
+	for _, test := range tests {
+		z, x, y, err := QuadKeyToTMS(test.quadKey)
+		if err != nil || z != test.z || x != test.x || y != test.y {
+			t.Errorf("QuadKeyToTMS(%q) = %d, %d, %d, %v, want %d, %d, %d", test.quadKey, z, x, y, err, test.z, test.x, test.y)
+		}
+		quadKey, err := TMSToQuadKey(test.z, test.x, test.y)
+		if err != nil || quadKey != test.quadKey {
+			t.Errorf("TMSToQuadKey(%d, %d, %d) = %q, %v, want %q", test.z, test.x, test.y, quadKey, err, test.quadKey)
+		}
+	}
+}
+
+func TestTMSRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		level := uint(r.Intn(MaxLevel + 1))
+		n := tilesPerSide(level)
+		quadKey := TileXYToQuadKey(r.Intn(n), r.Intn(n), level)
+		z, x, y, err := QuadKeyToTMS(quadKey)
+		if err != nil {
+			t.Fatalf("QuadKeyToTMS(%q): %v", quadKey, err)
+		}
+		if got, err := TMSToQuadKey(z, x, y); err != nil || got != quadKey {
+			t.Fatalf("TMSToQuadKey(QuadKeyToTMS(%q)) = %q, %v", quadKey, got, err)
+		}
+	}
+}
+
+func TestTMSInvalid(t *testing.T) {
+	if _, _, _, err := QuadKeyToTMS("0a"); err == nil {
+		t.Error("QuadKeyToTMS accepted a bad digit")
+	}
+	for _, c := range [][3]int{{-1, 0, 0}, {MaxLevel + 1, 0, 0}, {1, 2, 0}, {1, 0, 2}, {1, 0, -1}} {
+		if _, err := TMSToQuadKey(c[0], c[1], c[2]); err == nil {
+			t.Errorf("TMSToQuadKey(%d, %d, %d) should fail", c[0], c[1], c[2])
+		}
+	}
+}
+
+func TestSlippyToQuadKey(t *testing.T) {
+	tests := []struct {
+		z, x, y int
+		quadKey string
+	}{
+		{0, 0, 0, ""},
+		{1, 0, 0, "0"},
+		{1, 1, 1, "3"},
+		{3, 4, 2, "120"},
+	}
+	for _, test := range tests {
+		quadKey, err := SlippyToQuadKey(test.z, test.x, test.y)
+		if err != nil || quadKey != test.quadKey {
+			t.Errorf("SlippyToQuadKey(%d, %d, %d) = %q, %v, want %q", test.z, test.x, test.y, quadKey, err, test.quadKey)
+		}
+		if z, x, y := TileXYToSlippy(test.x, test.y, uint(test.z)); z != test.z || x != test.x || y != test.y {
+			t.Errorf("TileXYToSlippy(%d, %d, %d) = %d, %d, %d", test.x, test.y, test.z, z, x, y)
+		}
+	}
+	for _, c := range [][3]int{{0, 5, 5}, {0, 0, 1}, {-1, 0, 0}, {MaxLevel + 1, 0, 0}, {2, 4, 0}, {2, 0, -1}} {
+		if quadKey, err := SlippyToQuadKey(c[0], c[1], c[2]); err == nil {
+			t.Errorf("SlippyToQuadKey(%d, %d, %d) = %q, want an error", c[0], c[1], c[2], quadKey)
+		}
+	}
+}
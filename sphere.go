@@ -0,0 +1,351 @@
+// Quadkeys project sphere.go
+package Quadkeys
+
+import (
+	"math"
+)
+
+/// <summary>
+/// Determines the great-circle distance between two points with the
+/// haversine formula on a sphere of radius EarthRadius. The formula is
+/// well conditioned for nearby points and, working on longitude
+/// differences through a periodic sine, needs no special handling across
+/// the antimeridian or near the poles.
+/// </summary>
+/// <param name="lat1">Latitude of the first point, in degrees.</param>
+/// <param name="lon1">Longitude of the first point, in degrees.</param>
+/// <param name="lat2">Latitude of the second point, in degrees.</param>
+/// <param name="lon2">Longitude of the second point, in degrees.</param>
+/// <returns>The distance, in meters.</returns>
+func HaversineMeters(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+/// <summary>
+/// Determines the great-circle distance between the centers of two tiles,
+/// for clustering nearby tiles. Each center is found with QuadKeyToCenter.
+/// </summary>
+/// <param name="quadKeyA">QuadKey of the first tile.</param>
+/// <param name="quadKeyB">QuadKey of the second tile.</param>
+/// <param name="meters">Output parameter receiving the distance, in meters.</param>
+/// <param name="err">Output parameter receiving an error if either QuadKey is invalid.</param>
+func Distance(quadKeyA string, quadKeyB string) (meters float64, err error) {
+	latA, lonA, err := QuadKeyToCenter(quadKeyA)
+	if err != nil {
+		return 0, err
+	}
+	latB, lonB, err := QuadKeyToCenter(quadKeyB)
+	if err != nil {
+		return 0, err
+	}
+	return HaversineMeters(latA, lonA, latB, lonB), nil
+}
+
+/// <summary>
+/// Determines the initial great-circle bearing from the center of one tile
+/// to the center of another, for drawing direction arrows between tiles.
+/// The great circle takes the short way round, crossing the antimeridian
+/// when that is shorter. Coinciding tiles have bearing 0.
+/// </summary>
+/// <param name="quadKeyA">QuadKey of the start tile.</param>
+/// <param name="quadKeyB">QuadKey of the end tile.</param>
+/// <param name="degrees">Output parameter receiving the bearing, in degrees
+/// clockwise from north in [0, 360).</param>
+/// <param name="err">Output parameter receiving an error if either QuadKey is invalid.</param>
+func Bearing(quadKeyA string, quadKeyB string) (degrees float64, err error) {
+	latA, lonA, err := QuadKeyToCenter(quadKeyA)
+	if err != nil {
+		return 0, err
+	}
+	latB, lonB, err := QuadKeyToCenter(quadKeyB)
+	if err != nil {
+		return 0, err
+	}
+	if latA == latB && lonA == lonB {
+		return 0, nil
+	}
+	return initialBearing(latA, lonA, latB, lonB), nil
+}
+
+/// <summary>
+/// Determines the ground distance from a point to where a straight path
+/// leaving it at a given bearing exits the point's tile, for scheduling the
+/// next geofence check. The exit point is found in pixel space, treating
+/// the path as straight on the Mercator map within the tile (a rhumb line),
+/// and the distance to it is then measured on the sphere.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="bearingDeg">Compass bearing of the path, in degrees clockwise
+/// from north.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The distance to the tile edge, in meters.</returns>
+func DistanceToTileEdge(latitude float64, longitude float64, bearingDeg float64, levelOfDetail uint) float64 {
+	x, y := LatLongToPixelXYFloat(latitude, longitude, levelOfDetail)
+	tileCount := tilesPerSide(levelOfDetail)
+	tileX, tileY := tileIndex(x/TileSize, tileCount), tileIndex(y/TileSize, tileCount)
+
+	dx := math.Sin(bearingDeg * math.Pi / 180)
+	dy := -math.Cos(bearingDeg * math.Pi / 180)
+	t := math.Inf(1)
+	if dx > 0 {
+		t = math.Min(t, (float64(tileX+1)*TileSize-x)/dx)
+	} else if dx < 0 {
+		t = math.Min(t, (float64(tileX)*TileSize-x)/dx)
+	}
+	if dy > 0 {
+		t = math.Min(t, (float64(tileY+1)*TileSize-y)/dy)
+	} else if dy < 0 {
+		t = math.Min(t, (float64(tileY)*TileSize-y)/dy)
+	}
+	t = math.Max(t, 0)
+
+	exitLat, exitLon := pixelXYToLatLongFloat(x+t*dx, y+t*dy, levelOfDetail)
+	return HaversineMeters(latitude, longitude, exitLat, exitLon)
+}
+
+/// <summary>
+/// Determines the QuadKey of the tile containing the geodesic midpoint of
+/// two points: the point halfway along the great circle between them, not
+/// the average of their latitudes and longitudes, which drifts badly over
+/// long distances and across the antimeridian.
+/// </summary>
+/// <param name="lat1">Latitude of the first point, in degrees.</param>
+/// <param name="lon1">Longitude of the first point, in degrees.</param>
+/// <param name="lat2">Latitude of the second point, in degrees.</param>
+/// <param name="lon2">Longitude of the second point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKey of the midpoint's tile.</returns>
+func MidpointQuadKey(lat1 float64, lon1 float64, lat2 float64, lon2 float64, levelOfDetail uint) string {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	lambda1 := lon1 * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	bx := math.Cos(phi2) * math.Cos(dLambda)
+	by := math.Cos(phi2) * math.Sin(dLambda)
+	phi := math.Atan2(math.Sin(phi1)+math.Sin(phi2), math.Sqrt((math.Cos(phi1)+bx)*(math.Cos(phi1)+bx)+by*by))
+	lambda := lambda1 + math.Atan2(by, math.Cos(phi1)+bx)
+
+	latitude := phi * 180 / math.Pi
+	longitude := math.Mod(lambda*180/math.Pi+540, 360) - 180
+	return LatLongToQuadKey(latitude, longitude, levelOfDetail)
+}
+
+/// <summary>
+/// Solves the direct geodesic problem on a sphere of radius EarthRadius:
+/// the point reached by travelling a distance along a great circle from a
+/// start point at an initial bearing. The longitude is wrapped into
+/// [-180, 180).
+/// </summary>
+/// <param name="latitude">Latitude of the start point, in degrees.</param>
+/// <param name="longitude">Longitude of the start point, in degrees.</param>
+/// <param name="bearingDeg">Initial bearing, in degrees clockwise from north.</param>
+/// <param name="distanceMeters">Distance to travel, in meters.</param>
+/// <returns>The latitude and longitude of the destination, in degrees.</returns>
+func destinationPoint(latitude float64, longitude float64, bearingDeg float64, distanceMeters float64) (float64, float64) {
+	phi1 := latitude * math.Pi / 180
+	lambda1 := longitude * math.Pi / 180
+	theta := bearingDeg * math.Pi / 180
+	delta := distanceMeters / EarthRadius
+
+	phi2 := math.Asin(clip(math.Sin(phi1)*math.Cos(delta)+math.Cos(phi1)*math.Sin(delta)*math.Cos(theta), -1, 1))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+
+	return phi2 * 180 / math.Pi, math.Mod(math.Mod(lambda2*180/math.Pi+180, 360)+360, 360) - 180
+}
+
+/// <summary>
+/// Determines the QuadKey of the tile reached by travelling a distance
+/// along a great circle from a point at an initial bearing, for
+/// dead-reckoning projections. The destination longitude wraps across the
+/// antimeridian, and a destination beyond the Mercator latitude limits is
+/// clamped into the top or bottom row of tiles.
+/// </summary>
+/// <param name="latitude">Latitude of the start point, in degrees.</param>
+/// <param name="longitude">Longitude of the start point, in degrees.</param>
+/// <param name="bearingDeg">Initial bearing, in degrees clockwise from north.</param>
+/// <param name="distanceMeters">Distance to travel, in meters.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKey of the destination's tile.</returns>
+func DestinationQuadKey(latitude float64, longitude float64, bearingDeg float64, distanceMeters float64, levelOfDetail uint) string {
+	lat, lon := destinationPoint(latitude, longitude, bearingDeg, distanceMeters)
+	return LatLongToQuadKey(lat, lon, levelOfDetail)
+}
+
+/// <summary>
+/// Determines the initial great-circle bearing from one point to another.
+/// </summary>
+/// <param name="lat1">Latitude of the start point, in degrees.</param>
+/// <param name="lon1">Longitude of the start point, in degrees.</param>
+/// <param name="lat2">Latitude of the end point, in degrees.</param>
+/// <param name="lon2">Longitude of the end point, in degrees.</param>
+/// <returns>The bearing, in degrees clockwise from north in [0, 360).</returns>
+func initialBearing(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+/// <summary>
+/// Determines a bounding box containing the spherical circle of a given
+/// radius around a point. A circle reaching a pole spans all longitudes;
+/// one crossing the antimeridian yields a box whose minimum longitude is
+/// greater than its maximum longitude.
+/// </summary>
+/// <param name="latitude">Latitude of the center, in degrees.</param>
+/// <param name="longitude">Longitude of the center, in degrees.</param>
+/// <param name="radiusMeters">Radius of the circle, in meters.</param>
+/// <returns>The box as {minLat, minLong, maxLat, maxLong}, in degrees.</returns>
+func radiusBox(latitude float64, longitude float64, radiusMeters float64) [4]float64 {
+	delta := radiusMeters / EarthRadius * 180 / math.Pi
+	minLat, maxLat := latitude-delta, latitude+delta
+	if minLat <= -90 || maxLat >= 90 {
+		return [4]float64{math.Max(minLat, -90), MinLongitude, math.Min(maxLat, 90), MaxLongitude}
+	}
+	ratio := math.Sin(delta*math.Pi/180) / math.Cos(latitude*math.Pi/180)
+	if ratio >= 1 {
+		return [4]float64{minLat, MinLongitude, maxLat, MaxLongitude}
+	}
+	dLong := math.Asin(ratio) * 180 / math.Pi
+	west, east := longitude-dLong, longitude+dLong
+	if west < MinLongitude {
+		west += 360
+	}
+	if east > MaxLongitude {
+		east -= 360
+	}
+	return [4]float64{minLat, west, maxLat, east}
+}
+
+/// <summary>
+/// Determines the tiles whose centers lie within a spherical radius of a
+/// point and pass an additional test. As with CoverBoundingBox, nothing is
+/// enumerated when the radius's bounding box holds more than MaxCoverTiles
+/// tiles.
+/// </summary>
+/// <param name="latitude">Latitude of the center, in degrees.</param>
+/// <param name="longitude">Longitude of the center, in degrees.</param>
+/// <param name="radiusMeters">Radius, in meters.</param>
+/// <param name="levelOfDetail">Level of detail of the tiles.</param>
+/// <param name="keep">Called with each candidate tile center and its
+/// distance from the point; nil keeps every tile within the radius.</param>
+/// <returns>The QuadKeys of the kept tiles, in TilesForBoundingBox order, or
+/// nil if the level is above MaxLevel, the radius is negative or NaN, or
+/// the candidates exceed MaxCoverTiles.</returns>
+func tilesWithinRadius(latitude float64, longitude float64, radiusMeters float64, levelOfDetail uint, keep func(tileLat float64, tileLon float64, distance float64) bool) []string {
+	if validLevel(levelOfDetail) != nil || !(radiusMeters >= 0) {
+		return nil
+	}
+	box := radiusBox(latitude, longitude, radiusMeters)
+	if CountTilesForBoundingBox(box, levelOfDetail) > MaxCoverTiles {
+		return nil
+	}
+	var keys []string
+	for _, quadKey := range TilesForBoundingBox(box, levelOfDetail) {
+		tileX, tileY, _ := QuadKeyToTileXY(quadKey)
+		tileLat, tileLon := tileCenter(tileX, tileY, levelOfDetail)
+		distance := HaversineMeters(latitude, longitude, tileLat, tileLon)
+		if distance <= radiusMeters && (keep == nil || keep(tileLat, tileLon, distance)) {
+			keys = append(keys, quadKey)
+		}
+	}
+	return keys
+}
+
+/// <summary>
+/// Determines the tiles whose centers fall within a sector (pie slice)
+/// around a point, such as an antenna footprint. A tile center is inside
+/// when its great-circle distance is at most radiusMeters and the initial
+/// bearing to it lies clockwise from startBearing to endBearing; a sector
+/// whose start is greater than its end wraps through north, and a sector
+/// spanning 360 degrees or more is a full circle.
+/// </summary>
+/// <param name="centerLat">Latitude of the center, in degrees.</param>
+/// <param name="centerLon">Longitude of the center, in degrees.</param>
+/// <param name="radiusMeters">Radius of the sector, in meters.</param>
+/// <param name="startBearing">Bearing of the sector's first edge, in degrees.</param>
+/// <param name="endBearing">Bearing of the sector's second edge, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the tiles, in TilesForBoundingBox order, or nil
+/// if the sector's bounding box holds more than MaxCoverTiles tiles.</returns>
+func TilesInSector(centerLat float64, centerLon float64, radiusMeters float64, startBearing float64, endBearing float64, levelOfDetail uint) []string {
+	full := endBearing-startBearing >= 360
+	startBearing = math.Mod(math.Mod(startBearing, 360)+360, 360)
+	endBearing = math.Mod(math.Mod(endBearing, 360)+360, 360)
+	return tilesWithinRadius(centerLat, centerLon, radiusMeters, levelOfDetail, func(tileLat float64, tileLon float64, distance float64) bool {
+		if full || distance == 0 {
+			return true
+		}
+		bearing := initialBearing(centerLat, centerLon, tileLat, tileLon)
+		if startBearing <= endBearing {
+			return bearing >= startBearing && bearing <= endBearing
+		}
+		return bearing >= startBearing || bearing <= endBearing
+	})
+}
+
+/// <summary>
+/// Determines the tiles whose centers fall within an oriented ellipse
+/// around a point, such as a GPS error ellipse. Each candidate tile center
+/// is expressed in the ellipse's local frame from its great-circle
+/// distance and initial bearing from the center, then kept if its
+/// normalized distance (u/a)^2 + (v/b)^2 is at most 1.
+/// </summary>
+/// <param name="centerLat">Latitude of the center, in degrees.</param>
+/// <param name="centerLon">Longitude of the center, in degrees.</param>
+/// <param name="semiMajorMeters">Semi-axis along the orientation, in meters.</param>
+/// <param name="semiMinorMeters">Semi-axis across the orientation, in meters.</param>
+/// <param name="orientationDeg">Bearing of the major axis, in degrees clockwise
+/// from north.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the tiles, in TilesForBoundingBox order, or nil
+/// if either semi-axis is not positive or the ellipse's bounding box holds
+/// more than MaxCoverTiles tiles.</returns>
+func TilesInEllipse(centerLat float64, centerLon float64, semiMajorMeters float64, semiMinorMeters float64, orientationDeg float64, levelOfDetail uint) []string {
+	if semiMajorMeters <= 0 || semiMinorMeters <= 0 {
+		return nil
+	}
+	radius := math.Max(semiMajorMeters, semiMinorMeters)
+	return tilesWithinRadius(centerLat, centerLon, radius, levelOfDetail, func(tileLat float64, tileLon float64, distance float64) bool {
+		alpha := (initialBearing(centerLat, centerLon, tileLat, tileLon) - orientationDeg) * math.Pi / 180
+		u := distance * math.Cos(alpha) / semiMajorMeters
+		v := distance * math.Sin(alpha) / semiMinorMeters
+		return u*u+v*v <= 1
+	})
+}
+
+/// <summary>
+/// Determines the tiles whose centers a satellite can see: those within
+/// its horizon footprint, the spherical cap bounded by the Earth's limb.
+/// Seen from altitude h, the limb lies at a central angle of
+/// acos(R / (R + h)) from the sub-satellite point, with R = EarthRadius.
+/// </summary>
+/// <param name="subLat">Latitude of the sub-satellite point, in degrees.</param>
+/// <param name="subLon">Longitude of the sub-satellite point, in degrees.</param>
+/// <param name="altitudeMeters">Altitude of the satellite above the surface, in meters.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The QuadKeys of the visible tiles, in TilesForBoundingBox order,
+/// or nil if the altitude is not positive or the footprint's bounding box
+/// holds more than MaxCoverTiles tiles.</returns>
+func VisibleTiles(subLat float64, subLon float64, altitudeMeters float64, levelOfDetail uint) []string {
+	if altitudeMeters <= 0 {
+		return nil
+	}
+	horizonMeters := EarthRadius * math.Acos(EarthRadius/(EarthRadius+altitudeMeters))
+	return tilesWithinRadius(subLat, subLon, horizonMeters, levelOfDetail, nil)
+}
@@ -0,0 +1,113 @@
+// Quadkeys project sphere_test.go
+package Quadkeys
+
+import (
+	"math"
+	"testing"
+)
+
+func closeMeters(got float64, want float64) bool {
+	return math.Abs(got-want) <= 1e-6*math.Max(1, want)
+}
+
+func TestHaversineMeters(t *testing.T) {
+	degree := EarthRadius * math.Pi / 180
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same point", 47.6, -122.3, 47.6, -122.3, 0},
+		{"equator degree", 0, 10, 0, 11, degree},
+		{"meridian degree", 10, 5, 11, 5, degree},
+		{"pole to pole", 90, 0, -90, 0, math.Pi * EarthRadius},
+		{"antipodes on equator", 0, 0, 0, 180, math.Pi * EarthRadius},
+		{"across antimeridian", 0, 179.9, 0, -179.9, 0.2 * degree},
+		{"antimeridian wrapped", 0, 180, 0, -180, 0},
+		{"over north pole", 89.9, 0, 89.9, 180, 0.2 * degree},
+		{"over south pole", -89.9, 90, -89.9, -90, 0.2 * degree},
+		{"around north pole", 90, 0, 90, 123, 0},
+	}
+	for _, test := range tests {
+		got := HaversineMeters(test.lat1, test.lon1, test.lat2, test.lon2)
+		if !closeMeters(got, test.want) {
+			t.Errorf("%s: HaversineMeters = %f, want %f", test.name, got, test.want)
+		}
+		if back := HaversineMeters(test.lat2, test.lon2, test.lat1, test.lon1); back != got {
+			t.Errorf("%s: HaversineMeters not symmetric: %f and %f", test.name, got, back)
+		}
+	}
+}
+
+func TestDistanceAcrossAntimeridian(t *testing.T) {
+	for level := uint(2); level <= MaxLevel; level += 3 {
+		last := tilesPerSide(level) - 1
+		row := last / 3
+		east := TileXYToQuadKey(last, row, level)
+		west := TileXYToQuadKey(0, row, level)
+		across, err := Distance(east, west)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inside, err := Distance(west, TileXYToQuadKey(1, row, level))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeMeters(across, inside) {
+			t.Errorf("level %d: distance across the antimeridian %f, neighboring tiles %f", level, across, inside)
+		}
+	}
+}
+
+func TestDistanceNearPoles(t *testing.T) {
+	for level := uint(1); level <= MaxLevel; level += 2 {
+		n := tilesPerSide(level)
+		for _, row := range []int{0, n - 1} {
+			a := TileXYToQuadKey(0, row, level)
+			b := TileXYToQuadKey(n/2, row, level)
+			lat, _, err := QuadKeyToCenter(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Distance(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 2 * (90 - math.Abs(lat)) * EarthRadius * math.Pi / 180
+			if !closeMeters(got, want) {
+				t.Errorf("level %d row %d: distance over the pole %f, want %f", level, row, got, want)
+			}
+		}
+	}
+}
+
+func TestDistanceInvalid(t *testing.T) {
+	if _, err := Distance("0", "4"); err == nil {
+		t.Error("Distance accepted an invalid QuadKey")
+	}
+}
+
+func TestRadiusTilesLimit(t *testing.T) {
+	keys := VisibleTiles(0, 0, 400000, 6)
+	if len(keys) == 0 {
+		t.Fatal("VisibleTiles at level 6 returned no tiles")
+	}
+	for _, quadKey := range keys {
+		lat, lon, _ := QuadKeyToCenter(quadKey)
+		if distance := HaversineMeters(0, 0, lat, lon); distance > EarthRadius*math.Acos(EarthRadius/(EarthRadius+400000)) {
+			t.Errorf("tile %q is %v meters away, beyond the horizon", quadKey, distance)
+		}
+	}
+	if keys := VisibleTiles(0, 0, 35786000, 15); keys != nil {
+		t.Errorf("VisibleTiles past MaxCoverTiles returned %d tiles, want nil", len(keys))
+	}
+	if keys := TilesInSector(0, 0, 5000000, 0, 90, 16); keys != nil {
+		t.Errorf("TilesInSector past MaxCoverTiles returned %d tiles, want nil", len(keys))
+	}
+	if keys := TilesInEllipse(0, 0, 5000000, 100, 0, 16); keys != nil {
+		t.Errorf("TilesInEllipse past MaxCoverTiles returned %d tiles, want nil", len(keys))
+	}
+	if keys := TilesInSector(0, 0, 1000, 0, 360, MaxLevel+1); keys != nil {
+		t.Errorf("TilesInSector above MaxLevel returned %d tiles, want nil", len(keys))
+	}
+}
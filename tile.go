@@ -0,0 +1,116 @@
+// Quadkeys project tile.go
+package Quadkeys
+
+import (
+	"encoding/json"
+)
+
+/// <summary>
+/// A tile, identified by its tile XY coordinates and level of detail.
+/// </summary>
+type Tile struct {
+	X, Y  int
+	Level uint
+}
+
+/// <summary>
+/// Converts a QuadKey into a tile.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The tile, and an error if the QuadKey is invalid.</returns>
+func TileFromQuadKey(quadKey string) (Tile, error) {
+	tileX, tileY, levelOfDetail, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return Tile{}, err
+	}
+	return Tile{X: tileX, Y: tileY, Level: levelOfDetail}, nil
+}
+
+/// <summary>
+/// Converts the tile into its QuadKey.
+/// </summary>
+/// <returns>A string containing the QuadKey.</returns>
+func (t Tile) QuadKey() string {
+	return TileXYToQuadKey(t.X, t.Y, t.Level)
+}
+
+/// <summary>
+/// Determines the parent of the tile, the tile one level up that contains
+/// it. The level 0 tile is its own parent.
+/// </summary>
+/// <returns>The parent tile.</returns>
+func (t Tile) Parent() Tile {
+	if t.Level == 0 {
+		return t
+	}
+	return Tile{X: t.X >> 1, Y: t.Y >> 1, Level: t.Level - 1}
+}
+
+/// <summary>
+/// Determines the four children of the tile, the tiles one level down that
+/// it contains, in the same northwest, northeast, southwest, southeast
+/// order as Children. The tile is expected to be shallower than MaxLevel.
+/// </summary>
+/// <returns>The child tiles.</returns>
+func (t Tile) Children() [4]Tile {
+	var children [4]Tile
+	for i := range children {
+		children[i] = Tile{X: t.X<<1 | i&1, Y: t.Y<<1 | i>>1, Level: t.Level + 1}
+	}
+	return children
+}
+
+/// <summary>
+/// Encodes the tile as its QuadKey, implementing encoding.TextMarshaler.
+/// </summary>
+/// <returns>The QuadKey's digits, and an error if the level is above
+/// MaxLevel or a coordinate is outside the map.</returns>
+func (t Tile) MarshalText() ([]byte, error) {
+	quadKey, err := TileXYToQuadKeyErr(t.X, t.Y, t.Level)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(quadKey), nil
+}
+
+/// <summary>
+/// Decodes the tile from its QuadKey, implementing encoding.TextUnmarshaler.
+/// </summary>
+/// <param name="text">The QuadKey's digits.</param>
+/// <returns>An error if the text is not a valid QuadKey.</returns>
+func (t *Tile) UnmarshalText(text []byte) error {
+	tile, err := TileFromQuadKey(string(text))
+	if err != nil {
+		return err
+	}
+	*t = tile
+	return nil
+}
+
+/// <summary>
+/// Encodes the tile as a JSON string holding its QuadKey, implementing
+/// json.Marshaler.
+/// </summary>
+/// <returns>The JSON encoding, and an error if the level is above MaxLevel
+/// or a coordinate is outside the map.</returns>
+func (t Tile) MarshalJSON() ([]byte, error) {
+	text, err := t.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+/// <summary>
+/// Decodes the tile from a JSON string holding its QuadKey, implementing
+/// json.Unmarshaler.
+/// </summary>
+/// <param name="data">The JSON encoding.</param>
+/// <returns>An error if the data is not a JSON string holding a valid QuadKey.</returns>
+func (t *Tile) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return t.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,68 @@
+// Quadkeys project tile_test.go
+package Quadkeys
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestTileMarshalRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tiles := []Tile{{}, {X: 1, Y: 0, Level: 1}, {X: 1<<MaxLevel - 1, Y: 1<<MaxLevel - 1, Level: MaxLevel}}
+	for i := 0; i < 200; i++ {
+		level := uint(r.Intn(MaxLevel + 1))
+		n := tilesPerSide(level)
+		tiles = append(tiles, Tile{X: r.Intn(n), Y: r.Intn(n), Level: level})
+	}
+	for _, tile := range tiles {
+		text, err := tile.MarshalText()
+		if err != nil || string(text) != tile.QuadKey() {
+			t.Fatalf("%+v.MarshalText() = %q, %v, want %q", tile, text, err, tile.QuadKey())
+		}
+		var fromText Tile
+		if err := fromText.UnmarshalText(text); err != nil || fromText != tile {
+			t.Fatalf("UnmarshalText(%q) = %+v, %v, want %+v", text, fromText, err, tile)
+		}
+
+		data, err := json.Marshal(map[string]Tile{"tile": tile})
+		if err != nil {
+			t.Fatalf("json.Marshal(%+v): %v", tile, err)
+		}
+		var fromJSON map[string]Tile
+		if err := json.Unmarshal(data, &fromJSON); err != nil || fromJSON["tile"] != tile {
+			t.Fatalf("json.Unmarshal(%s) = %+v, %v, want %+v", data, fromJSON["tile"], err, tile)
+		}
+	}
+}
+
+func TestTileMarshalInvalid(t *testing.T) {
+	for _, tile := range []Tile{
+		{Level: 30},
+		{Level: MaxLevel + 1},
+		{X: -1, Y: 5, Level: 3},
+		{X: 8, Y: 0, Level: 3},
+		{X: 0, Y: 8, Level: 3},
+		{X: 1, Y: 0, Level: 0},
+	} {
+		if text, err := tile.MarshalText(); err == nil {
+			t.Errorf("%+v.MarshalText() = %q, want an error", tile, text)
+		}
+		if data, err := json.Marshal(tile); err == nil {
+			t.Errorf("json.Marshal(%+v) = %s, want an error", tile, data)
+		}
+	}
+}
+
+func TestTileUnmarshalInvalid(t *testing.T) {
+	for _, text := range []string{"4", "01a", "000000000000000000000000"} {
+		var tile Tile
+		if err := tile.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %+v, want an error", text, tile)
+		}
+	}
+	var tile Tile
+	if err := json.Unmarshal([]byte("12"), &tile); err == nil {
+		t.Error("json.Unmarshal accepted a number")
+	}
+}
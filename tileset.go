@@ -0,0 +1,353 @@
+// Quadkeys project tileset.go
+package Quadkeys
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/// <summary>
+/// Determines the bit representing a tile's quadrant within its parent:
+/// bit 0 for digit 0 (NW) through bit 3 for digit 3 (SE).
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <returns>The quadrant bit, or 0 if the QuadKey is empty or invalid.</returns>
+func SiblingMask(quadKey string) uint8 {
+	if quadKey == "" || !validQuadKey(quadKey) {
+		return 0
+	}
+	return 1 << (quadKey[len(quadKey)-1] - '0')
+}
+
+/// <summary>
+/// Accumulates the SiblingMask of each tile into a 4-bit mask per parent,
+/// so a parent whose four children are all present has mask 0x0F.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles.</param>
+/// <returns>The accumulated masks keyed by parent QuadKey.</returns>
+func SiblingGroupMasks(keys []string) map[string]uint8 {
+	masks := make(map[string]uint8)
+	for _, quadKey := range keys {
+		if mask := SiblingMask(quadKey); mask != 0 {
+			masks[quadKey[:len(quadKey)-1]] |= mask
+		}
+	}
+	return masks
+}
+
+/// <summary>
+/// Determines the minimal set of QuadKey prefixes that covers exactly the
+/// given tiles. A prefix is returned only when the input contains all of
+/// its descendants, either directly or through complete groups of four
+/// siblings, so every input key starts with exactly one returned prefix
+/// and no prefix covers a tile outside the input. Keys whose ancestor is
+/// also in the input are absorbed by that ancestor. Invalid keys are
+/// ignored.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles, of any mix of levels.</param>
+/// <returns>The covering prefixes, sorted.</returns>
+func PrefixCover(keys []string) []string {
+	set := make(map[string]bool)
+	maxLength := 0
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		set[quadKey] = true
+		if len(quadKey) > maxLength {
+			maxLength = len(quadKey)
+		}
+	}
+
+	// Drop keys that already lie under another input key.
+	for quadKey := range set {
+		for i := 0; i < len(quadKey); i++ {
+			if set[quadKey[:i]] {
+				delete(set, quadKey)
+				break
+			}
+		}
+	}
+
+	// Merge complete sibling groups bottom up, so merges cascade.
+	for length := maxLength; length > 0; length-- {
+		var level []string
+		for quadKey := range set {
+			if len(quadKey) == length {
+				level = append(level, quadKey)
+			}
+		}
+		for parent, mask := range SiblingGroupMasks(level) {
+			if mask == 0x0F {
+				for digit := byte('0'); digit <= '3'; digit++ {
+					delete(set, parent+string(digit))
+				}
+				set[parent] = true
+			}
+		}
+	}
+
+	prefixes := make([]string, 0, len(set))
+	for quadKey := range set {
+		prefixes = append(prefixes, quadKey)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+/// <summary>
+/// Determines the descendants of a tile at a deeper level of detail, in
+/// depth-first, digit-ascending order (which is also sorted order).
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="levelOfDetail">Level of detail of the descendants; the tile
+/// itself is returned if this is not deeper than the tile.</param>
+/// <returns>The QuadKeys of the 4^(levelOfDetail - len(quadKey)) descendants.</returns>
+func descend(quadKey string, levelOfDetail uint) []string {
+	keys := []string{quadKey}
+	for length := uint(len(quadKey)); length < levelOfDetail; length++ {
+		next := make([]string, 0, len(keys)*4)
+		for _, key := range keys {
+			next = append(next, key+"0", key+"1", key+"2", key+"3")
+		}
+		keys = next
+	}
+	return keys
+}
+
+/// <summary>
+/// Collects the valid keys of a tile set and every proper prefix of them,
+/// the two lookups SymmetricDifference walks the quadtree with.
+/// </summary>
+/// <param name="keys">QuadKeys of the tile set.</param>
+/// <returns>The set of keys, and the set of their proper prefixes.</returns>
+func tileSetTrie(keys []string) (set map[string]bool, prefixes map[string]bool) {
+	set = make(map[string]bool)
+	prefixes = make(map[string]bool)
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		set[quadKey] = true
+		for i := 0; i < len(quadKey); i++ {
+			prefixes[quadKey[:i]] = true
+		}
+	}
+	return
+}
+
+/// <summary>
+/// Determines the tiles present in exactly one of two tile sets, the
+/// undirected "what changed" between two versions of a region. Sets of
+/// mixed levels are compared by area, as if both were first expanded to a
+/// common level: a coarse tile in one set and its descendants in the other
+/// cancel out. Rather than enumerating descendants, both sets are walked
+/// together as a prefix trie, so the cost grows with the total length of
+/// the keys, not with the depth of the common level. The difference is
+/// returned as disjoint tiles, each as coarse as the inputs allow; expand
+/// them with Descendants for a single level. Invalid keys are ignored.
+/// </summary>
+/// <param name="a">QuadKeys of the first tile set.</param>
+/// <param name="b">QuadKeys of the second tile set.</param>
+/// <returns>The QuadKeys of the tiles covered by exactly one set, sorted.</returns>
+func SymmetricDifference(a []string, b []string) []string {
+	setA, belowA := tileSetTrie(a)
+	setB, belowB := tileSetTrie(b)
+
+	var difference []string
+	var walk func(quadKey string, inA bool, inB bool)
+	walk = func(quadKey string, inA bool, inB bool) {
+		inA = inA || setA[quadKey]
+		inB = inB || setB[quadKey]
+		switch {
+		case inA && inB:
+			return
+		case inA && !belowB[quadKey], inB && !belowA[quadKey]:
+			// Covered by one set with nothing of the other beneath it.
+			difference = append(difference, quadKey)
+			return
+		case !inA && !inB && !belowA[quadKey] && !belowB[quadKey]:
+			return
+		}
+		for digit := byte('0'); digit <= '3'; digit++ {
+			walk(quadKey+string(digit), inA, inB)
+		}
+	}
+	walk("", false, false)
+	sort.Strings(difference)
+	return difference
+}
+
+/// <summary>
+/// Determines the longest common prefix of two QuadKeys, which is the
+/// QuadKey of the deepest tile containing both.
+/// </summary>
+/// <param name="a">First QuadKey.</param>
+/// <param name="b">Second QuadKey.</param>
+/// <returns>The shared prefix.</returns>
+func commonPrefix(a string, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
+
+/// <summary>
+/// Determines the single tile that best summarizes a cluster of tiles: the
+/// deepest tile containing all of them, their common ancestor. Alongside it
+/// comes the inflation factor, the ratio of the ancestor's area to the area
+/// of the union of the input tiles. A factor near 1 means the ancestor is a
+/// tight summary; a high factor means the cluster straddles quadrant
+/// boundaries and is better split. Invalid keys are ignored.
+/// </summary>
+/// <param name="keys">QuadKeys of the cluster's tiles, of any mix of levels.</param>
+/// <returns>The ancestor's QuadKey and the inflation factor, or an empty
+/// string and 0 if there are no valid keys.</returns>
+func SummaryTile(keys []string) (string, float64) {
+	set := make(map[string]bool)
+	ancestor, first := "", true
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) {
+			continue
+		}
+		set[quadKey] = true
+		if first {
+			ancestor, first = quadKey, false
+		} else {
+			ancestor = commonPrefix(ancestor, quadKey)
+		}
+	}
+	if first {
+		return "", 0
+	}
+
+	// Tiles under another input tile add nothing to the union.
+	unionArea := 0.0
+	for quadKey := range set {
+		covered := false
+		for i := 0; i < len(quadKey) && !covered; i++ {
+			covered = set[quadKey[:i]]
+		}
+		if !covered {
+			unionArea += QuadKeyArea(quadKey)
+		}
+	}
+	return ancestor, QuadKeyArea(ancestor) / unionArea
+}
+
+/// <summary>
+/// Builds the shape key of a set of tiles at one level: the level followed
+/// by the sorted, distinct offsets of the tiles from their minimum X and
+/// minimum Y.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail of the tiles.</param>
+/// <param name="tiles">Tile XY coordinates; sorted in place.</param>
+/// <returns>The shape key.</returns>
+func clusterShapeKey(levelOfDetail int, tiles [][2]int) string {
+	minX, minY := math.MaxInt, math.MaxInt
+	for _, tile := range tiles {
+		if tile[0] < minX {
+			minX = tile[0]
+		}
+		if tile[1] < minY {
+			minY = tile[1]
+		}
+	}
+	sort.Slice(tiles, func(i int, j int) bool {
+		if tiles[i][0] != tiles[j][0] {
+			return tiles[i][0] < tiles[j][0]
+		}
+		return tiles[i][1] < tiles[j][1]
+	})
+	var builder strings.Builder
+	builder.WriteString(strconv.Itoa(levelOfDetail))
+	builder.WriteString(":")
+	for i, tile := range tiles {
+		if i > 0 && tile == tiles[i-1] {
+			continue
+		}
+		if i > 0 {
+			builder.WriteString(";")
+		}
+		builder.WriteString(strconv.Itoa(tile[0] - minX))
+		builder.WriteString(",")
+		builder.WriteString(strconv.Itoa(tile[1] - minY))
+	}
+	return builder.String()
+}
+
+/// <summary>
+/// Builds a canonical key for the shape of a tile cluster independent of
+/// where it sits on the map and how it is turned, so congruent clusters
+/// share a cache entry. The cluster is taken through each of the eight
+/// symmetries of the tile grid, the four 90 degree rotations with and
+/// without a mirror flip, and each image is translated so its minimum tile
+/// X and minimum tile Y are both 0. Each image is written as the level
+/// followed by its sorted, distinct relative offsets, for example
+/// "12:0,0;0,1;1,1", and the key is the lexicographically smallest of the
+/// eight. A cluster straddling the antimeridian is not unwrapped.
+/// </summary>
+/// <param name="keys">QuadKeys of the cluster's tiles, all at one level.</param>
+/// <returns>The shape key, or an empty string if there are no keys, a key
+/// is invalid, or the keys are at different levels.</returns>
+func NormalizedClusterKey(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	levelOfDetail := len(keys[0])
+	tiles := make([][2]int, 0, len(keys))
+	for _, quadKey := range keys {
+		if !validQuadKey(quadKey) || len(quadKey) != levelOfDetail {
+			return ""
+		}
+		tileX, tileY, _ := QuadKeyToTileXY(quadKey)
+		tiles = append(tiles, [2]int{tileX, tileY})
+	}
+
+	symmetries := []func(x int, y int) (int, int){
+		func(x int, y int) (int, int) { return x, y },
+		func(x int, y int) (int, int) { return -y, x },
+		func(x int, y int) (int, int) { return -x, -y },
+		func(x int, y int) (int, int) { return y, -x },
+		func(x int, y int) (int, int) { return -x, y },
+		func(x int, y int) (int, int) { return x, -y },
+		func(x int, y int) (int, int) { return y, x },
+		func(x int, y int) (int, int) { return -y, -x },
+	}
+	var best string
+	image := make([][2]int, len(tiles))
+	for i, symmetry := range symmetries {
+		for j, tile := range tiles {
+			image[j][0], image[j][1] = symmetry(tile[0], tile[1])
+		}
+		if key := clusterShapeKey(levelOfDetail, image); i == 0 || key < best {
+			best = key
+		}
+	}
+	return best
+}
+
+/// <summary>
+/// Computes an order-independent fingerprint of a tile set, for cheaply
+/// detecting whether a coverage changed between runs. Each distinct key is
+/// hashed (FNV-1a, then mixed) and the hashes are combined with XOR, so
+/// the same tiles in any order or with repeats hash equal. Different sets
+/// almost always hash differently, but as with any 64-bit hash collisions
+/// are possible, so equal hashes are not proof of equal sets.
+/// </summary>
+/// <param name="keys">QuadKeys of the tiles.</param>
+/// <returns>The fingerprint; 0 for an empty set.</returns>
+func TileSetHash(keys []string) uint64 {
+	seen := make(map[string]bool, len(keys))
+	var hash uint64
+	for _, quadKey := range keys {
+		if !seen[quadKey] {
+			seen[quadKey] = true
+			hash ^= quadKeyHash(quadKey)
+		}
+	}
+	return hash
+}
@@ -0,0 +1,83 @@
+// Quadkeys project tileset_test.go
+package Quadkeys
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSymmetricDifference(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want []string
+	}{
+		{nil, nil, nil},
+		{[]string{"0"}, nil, []string{"0"}},
+		{nil, []string{"12", "x"}, []string{"12"}},
+		{[]string{"0"}, []string{"0"}, nil},
+		{[]string{"0"}, []string{"00", "01", "02", "03"}, nil},
+		{[]string{"0"}, []string{"00"}, []string{"01", "02", "03"}},
+		{[]string{"00", "1"}, []string{"0"}, []string{"01", "02", "03", "1"}},
+		{[]string{""}, []string{"3"}, []string{"0", "1", "2"}},
+		{[]string{"0", "01"}, []string{"013"}, []string{"00", "010", "011", "012", "02", "03"}},
+	}
+	for _, test := range tests {
+		if got := SymmetricDifference(test.a, test.b); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SymmetricDifference(%q, %q) = %q, want %q", test.a, test.b, got, test.want)
+		}
+		if got := SymmetricDifference(test.b, test.a); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SymmetricDifference(%q, %q) = %q, want %q", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestSymmetricDifferenceDeepKey(t *testing.T) {
+	deep := "0123012301230123"
+	got := SymmetricDifference([]string{"0"}, []string{deep})
+	// Three siblings at each of the 15 levels below "0".
+	if len(got) != 45 {
+		t.Fatalf("SymmetricDifference of a tile and one deep descendant gave %d tiles, want 45", len(got))
+	}
+	for _, quadKey := range got {
+		if quadKey == deep || IsAncestor(quadKey, deep) || IsAncestor(deep, quadKey) {
+			t.Errorf("difference tile %q overlaps %q", quadKey, deep)
+		}
+	}
+}
+
+func TestNormalizedClusterKeySymmetries(t *testing.T) {
+	// An L tromino: (0,0), (0,1), (1,1) shifted to (10,20).
+	cluster := func(tiles [][2]int) []string {
+		keys := make([]string, len(tiles))
+		for i, tile := range tiles {
+			keys[i] = TileXYToQuadKey(10+tile[0], 20+tile[1], 8)
+		}
+		return keys
+	}
+	want := NormalizedClusterKey(cluster([][2]int{{0, 0}, {0, 1}, {1, 1}}))
+	for _, tiles := range [][][2]int{
+		{{5, 5}, {5, 6}, {6, 6}},
+		{{0, 0}, {1, 0}, {0, 1}},
+		{{1, 0}, {1, 1}, {0, 1}},
+		{{0, 0}, {1, 0}, {1, 1}},
+		{{1, 1}, {0, 1}, {0, 0}, {0, 0}},
+	} {
+		if got := NormalizedClusterKey(cluster(tiles)); got != want {
+			t.Errorf("NormalizedClusterKey(%v) = %q, want %q", tiles, got, want)
+		}
+	}
+	if got := NormalizedClusterKey(cluster([][2]int{{0, 0}, {1, 0}, {2, 0}})); got == want {
+		t.Errorf("a straight tromino shares the L tromino's key %q", got)
+	}
+	// An S tetromino and its mirror image, a Z, are congruent.
+	s := NormalizedClusterKey(cluster([][2]int{{1, 0}, {2, 0}, {0, 1}, {1, 1}}))
+	z := NormalizedClusterKey(cluster([][2]int{{0, 0}, {1, 0}, {1, 1}, {2, 1}}))
+	if s != z {
+		t.Errorf("S key %q differs from Z key %q", s, z)
+	}
+	for _, keys := range [][]string{nil, {"0", "01"}, {"4"}} {
+		if got := NormalizedClusterKey(keys); got != "" {
+			t.Errorf("NormalizedClusterKey(%q) = %q, want empty", keys, got)
+		}
+	}
+}
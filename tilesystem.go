@@ -0,0 +1,175 @@
+// Quadkeys project tilesystem.go
+package Quadkeys
+
+import (
+	"math"
+)
+
+/// <summary>
+/// A tile system with a custom tile size, such as the 512-pixel tiles
+/// served for high-density displays. Its methods mirror the package
+/// functions, which use TileSize. QuadKeys and tile XY coordinates do not
+/// depend on the tile size; only pixel coordinates and resolutions do.
+/// </summary>
+type TileSystem struct {
+	tileSize uint
+}
+
+/// <summary>
+/// Creates a tile system with a specified tile size. A tile size of 0,
+/// which would make every map empty, falls back to TileSize.
+/// </summary>
+/// <param name="tileSize">Width and height of a tile, in pixels.</param>
+/// <returns>The tile system.</returns>
+func NewTileSystem(tileSize uint) *TileSystem {
+	if tileSize == 0 {
+		tileSize = TileSize
+	}
+	return &TileSystem{tileSize: tileSize}
+}
+
+/// <summary>
+/// Determines the width and height of a tile, in pixels.
+/// </summary>
+/// <returns>The tile size in pixels.</returns>
+func (t *TileSystem) TileSize() uint {
+	return t.tileSize
+}
+
+/// <summary>
+/// Determines the map width and height (in pixels) at a specified level
+/// of detail.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The map width and height in pixels.</returns>
+func (t *TileSystem) MapSize(levelOfDetail uint) uint {
+	return t.tileSize << levelOfDetail
+}
+
+/// <summary>
+/// Determines the ground resolution (in meters per pixel) at a specified
+/// latitude and level of detail.
+/// </summary>
+/// <param name="latitude">Latitude (in degrees) at which to measure the
+/// ground resolution.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The ground resolution, in meters per pixel.</returns>
+func (t *TileSystem) GroundResolution(latitude float64, levelOfDetail uint) float64 {
+	latitude = clip(latitude, MinLatitude, MaxLatitude)
+	return math.Cos(latitude*math.Pi/180) * 2 * math.Pi * EarthRadius / float64(t.MapSize(levelOfDetail))
+}
+
+/// <summary>
+/// Determines the map scale at a specified latitude, level of detail,
+/// and screen resolution.
+/// </summary>
+/// <param name="latitude">Latitude (in degrees) at which to measure the
+/// map scale.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="screenDpi">Resolution of the screen, in dots per inch.</param>
+/// <returns>The map scale, expressed as the denominator N of the ratio 1 : N.</returns>
+func (t *TileSystem) MapScale(latitude float64, levelOfDetail uint, screenDpi uint) float64 {
+	return t.GroundResolution(latitude, levelOfDetail) * float64(screenDpi) / 0.0254
+}
+
+/// <summary>
+/// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
+/// into pixel XY coordinates at a specified level of detail.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="pixelX">Output parameter receiving the X coordinate in pixels.</param>
+/// <param name="pixelY">Output parameter receiving the Y coordinate in pixels.</param>
+func (t *TileSystem) LatLongToPixelXY(latitude float64, longitude float64, levelOfDetail uint) (pixelX int, pixelY int) {
+	x, y := LatLongToPixelXYFloat(latitude, longitude, levelOfDetail)
+	scale := float64(t.tileSize) / TileSize
+	mapSize := float64(t.MapSize(levelOfDetail))
+	pixelX = int(clip(x*scale, 0, mapSize-1))
+	pixelY = int(clip(y*scale, 0, mapSize-1))
+	return
+}
+
+/// <summary>
+/// Converts a pixel from pixel XY coordinates at a specified level of detail
+/// into latitude/longitude WGS-84 coordinates (in degrees).
+/// </summary>
+/// <param name="pixelX">X coordinate of the point, in pixels.</param>
+/// <param name="pixelY">Y coordinates of the point, in pixels.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="latitude">Output parameter receiving the latitude in degrees.</param>
+/// <param name="longitude">Output parameter receiving the longitude in degrees.</param>
+func (t *TileSystem) PixelXYToLatLong(pixelX int, pixelY int, levelOfDetail uint) (latitude float64, longitude float64) {
+	mapSize := float64(t.MapSize(levelOfDetail))
+	scale := float64(t.tileSize) / TileSize
+	x := clip(float64(pixelX), 0, mapSize-1) / scale
+	y := clip(float64(pixelY), 0, mapSize-1) / scale
+	return pixelXYToLatLongFloat(x, y, levelOfDetail)
+}
+
+/// <summary>
+/// Converts pixel XY coordinates into tile XY coordinates of the tile containing
+/// the specified pixel.
+/// </summary>
+/// <param name="pixelX">Pixel X coordinate.</param>
+/// <param name="pixelY">Pixel Y coordinate.</param>
+/// <param name="tileX">Output parameter receiving the tile X coordinate.</param>
+/// <param name="tileY">Output parameter receiving the tile Y coordinate.</param>
+func (t *TileSystem) PixelXYToTileXY(pixelX int, pixelY int) (tileX int, tileY int) {
+	tileX = pixelX / int(t.tileSize)
+	tileY = pixelY / int(t.tileSize)
+	return
+}
+
+/// <summary>
+/// Converts tile XY coordinates into pixel XY coordinates of the upper-left pixel
+/// of the specified tile.
+/// </summary>
+/// <param name="tileX">Tile X coordinate.</param>
+/// <param name="tileY">Tile Y coordinate.</param>
+/// <param name="pixelX">Output parameter receiving the pixel X coordinate.</param>
+/// <param name="pixelY">Output parameter receiving the pixel Y coordinate.</param>
+func (t *TileSystem) TileXYToPixelXY(tileX int, tileY int) (pixelX int, pixelY int) {
+	pixelX = tileX * int(t.tileSize)
+	pixelY = tileY * int(t.tileSize)
+	return
+}
+
+/// <summary>
+/// Converts a point from latitude/longitude WGS-84 coordinates (in degrees)
+/// into the QuadKey of the containing tile at a specified level of detail.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>A string containing the QuadKey.</returns>
+func (t *TileSystem) LatLongToQuadKey(latitude float64, longitude float64, levelOfDetail uint) string {
+	pixelX, pixelY := t.LatLongToPixelXY(latitude, longitude, levelOfDetail)
+	tileX, tileY := t.PixelXYToTileXY(pixelX, pixelY)
+	return TileXYToQuadKey(tileX, tileY, levelOfDetail)
+}
+
+/// <summary>
+/// Determines the pixel extent of a tile, minimum inclusive and maximum
+/// exclusive, as QuadKeyToPixelBounds does for the tile system's tile size.
+/// </summary>
+/// <param name="quadKey">QuadKey of the tile.</param>
+/// <param name="minX">Output parameter receiving the first pixel X coordinate.</param>
+/// <param name="minY">Output parameter receiving the first pixel Y coordinate.</param>
+/// <param name="maxX">Output parameter receiving the pixel X coordinate just past the tile.</param>
+/// <param name="maxY">Output parameter receiving the pixel Y coordinate just past the tile.</param>
+/// <param name="err">Output parameter receiving an error if the QuadKey is invalid.</param>
+func (t *TileSystem) QuadKeyToPixelBounds(quadKey string) (minX int, minY int, maxX int, maxY int, err error) {
+	tileX, tileY, _, err := QuadKeyToTileXYErr(quadKey)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	minX, minY = t.TileXYToPixelXY(tileX, tileY)
+	return minX, minY, minX + int(t.tileSize), minY + int(t.tileSize), nil
+}
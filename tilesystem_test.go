@@ -0,0 +1,29 @@
+// Quadkeys project tilesystem_test.go
+package Quadkeys
+
+import "testing"
+
+func TestNewTileSystemZeroSize(t *testing.T) {
+	tileSystem := NewTileSystem(0)
+	if tileSystem.TileSize() != TileSize {
+		t.Fatalf("NewTileSystem(0).TileSize() = %d, want %d", tileSystem.TileSize(), TileSize)
+	}
+	if tileX, tileY := tileSystem.PixelXYToTileXY(300, 600); tileX != 1 || tileY != 2 {
+		t.Errorf("PixelXYToTileXY(300, 600) = %d, %d, want 1, 2", tileX, tileY)
+	}
+}
+
+func TestTileSystemMatchesDefault(t *testing.T) {
+	tileSystem := NewTileSystem(TileSize)
+	for level := uint(1); level <= MaxLevel; level += 4 {
+		for _, point := range [][2]float64{{47.6, -122.3}, {0, 0}, {-33.9, 151.2}, {MaxLatitude, 180}} {
+			wantX, wantY := LatLongToPixelXY(point[0], point[1], level)
+			if x, y := tileSystem.LatLongToPixelXY(point[0], point[1], level); x != wantX || y != wantY {
+				t.Errorf("level %d %v: pixel %d, %d, want %d, %d", level, point, x, y, wantX, wantY)
+			}
+			if got, want := tileSystem.LatLongToQuadKey(point[0], point[1], level), LatLongToQuadKey(point[0], point[1], level); got != want {
+				t.Errorf("level %d %v: QuadKey %q, want %q", level, point, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Quadkeys project tracking.go
+package Quadkeys
+
+import (
+	"sort"
+	"sync"
+)
+
+/// <summary>
+/// Determines whether a moving point has left its tile between two
+/// readings, the primitive behind geofence entry and exit events. Each
+/// reading is assigned to a tile exactly as LatLongToQuadKey does, so a
+/// point on a tile boundary belongs to the tile east or south of it.
+/// </summary>
+/// <param name="prevLat">Latitude of the previous reading, in degrees.</param>
+/// <param name="prevLon">Longitude of the previous reading, in degrees.</param>
+/// <param name="curLat">Latitude of the current reading, in degrees.</param>
+/// <param name="curLon">Longitude of the current reading, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <param name="changed">Output parameter receiving true if the readings fall in different tiles.</param>
+/// <param name="from">Output parameter receiving the QuadKey of the previous reading.</param>
+/// <param name="to">Output parameter receiving the QuadKey of the current reading.</param>
+func TileChanged(prevLat float64, prevLon float64, curLat float64, curLon float64, levelOfDetail uint) (changed bool, from string, to string) {
+	from = LatLongToQuadKey(prevLat, prevLon, levelOfDetail)
+	to = LatLongToQuadKey(curLat, curLon, levelOfDetail)
+	return from != to, from, to
+}
+
+/// <summary>
+/// Describes a track crossing from one tile into another.
+/// </summary>
+type TileTransition struct {
+	// Index of the first track point in the new tile.
+	Index int
+	From  string
+	To    string
+}
+
+/// <summary>
+/// Determines every point along a track where it crosses into a different
+/// tile. The first point establishes the initial tile without emitting a
+/// transition, and consecutive points in the same tile emit nothing.
+/// </summary>
+/// <param name="lats">Latitudes of the track points, in degrees.</param>
+/// <param name="lons">Longitudes of the track points, in degrees.</param>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The transitions in track order, or nil if lats and lons differ
+/// in length.</returns>
+func TileTransitions(lats []float64, lons []float64, levelOfDetail uint) []TileTransition {
+	if len(lats) != len(lons) || len(lats) == 0 {
+		return nil
+	}
+	var transitions []TileTransition
+	current := LatLongToQuadKey(lats[0], lons[0], levelOfDetail)
+	for i := 1; i < len(lats); i++ {
+		next := LatLongToQuadKey(lats[i], lons[i], levelOfDetail)
+		if next != current {
+			transitions = append(transitions, TileTransition{Index: i, From: current, To: next})
+			current = next
+		}
+	}
+	return transitions
+}
+
+/// <summary>
+/// Maintains the set of tiles at a fixed level of detail that a live
+/// stream of points has occupied, reporting when a point enters a tile no
+/// earlier point reached. An OccupiedTiles is safe for concurrent use.
+/// </summary>
+type OccupiedTiles struct {
+	levelOfDetail uint
+	mutex         sync.Mutex
+	tiles         map[string]bool
+}
+
+/// <summary>
+/// Creates an empty OccupiedTiles at a specified level of detail.
+/// </summary>
+/// <param name="levelOfDetail">Level of detail, from 1 (lowest detail)
+/// to 23 (highest detail).</param>
+/// <returns>The new set.</returns>
+func NewOccupiedTiles(levelOfDetail uint) *OccupiedTiles {
+	return &OccupiedTiles{levelOfDetail: levelOfDetail, tiles: make(map[string]bool)}
+}
+
+/// <summary>
+/// Records a point, marking its tile as occupied.
+/// </summary>
+/// <param name="latitude">Latitude of the point, in degrees.</param>
+/// <param name="longitude">Longitude of the point, in degrees.</param>
+/// <param name="quadKey">Output parameter receiving the QuadKey of the point's tile.</param>
+/// <param name="isNew">Output parameter receiving true if the tile was not
+/// occupied before.</param>
+func (o *OccupiedTiles) Add(latitude float64, longitude float64) (quadKey string, isNew bool) {
+	quadKey = LatLongToQuadKey(latitude, longitude, o.levelOfDetail)
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.tiles[quadKey] {
+		return quadKey, false
+	}
+	o.tiles[quadKey] = true
+	return quadKey, true
+}
+
+/// <summary>
+/// Determines the number of occupied tiles.
+/// </summary>
+/// <returns>The number of occupied tiles.</returns>
+func (o *OccupiedTiles) Count() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return len(o.tiles)
+}
+
+/// <summary>
+/// Lists the occupied tiles.
+/// </summary>
+/// <returns>The QuadKeys of the occupied tiles, sorted.</returns>
+func (o *OccupiedTiles) Keys() []string {
+	o.mutex.Lock()
+	keys := make([]string, 0, len(o.tiles))
+	for quadKey := range o.tiles {
+		keys = append(keys, quadKey)
+	}
+	o.mutex.Unlock()
+	sort.Strings(keys)
+	return keys
+}
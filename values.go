@@ -0,0 +1,111 @@
+// Quadkeys project values.go
+package Quadkeys
+
+import (
+	"math"
+)
+
+/// <summary>
+/// Rolls a uniform-level tile-value grid up into coarser ancestor tiles,
+/// for building pyramid overviews. Each tile contributes to the ancestor
+/// whose QuadKey is its own with the last `levels` digits removed, so four
+/// children feed one parent per level. Only present tiles take part: a
+/// "mean" averages the descendants that have values rather than treating
+/// missing ones as zero. Invalid keys and keys shorter than `levels` are
+/// ignored.
+/// </summary>
+/// <param name="values">Values keyed by QuadKey, all at one level.</param>
+/// <param name="levels">Number of levels to roll up.</param>
+/// <param name="agg">Aggregation: "sum", "mean" or "max".</param>
+/// <returns>Aggregated values keyed by ancestor QuadKey, or nil if agg is
+/// unknown or levels is negative.</returns>
+func DownsampleTileValues(values map[string]float64, levels int, agg string) map[string]float64 {
+	if levels < 0 || (agg != "sum" && agg != "mean" && agg != "max") {
+		return nil
+	}
+
+	result := make(map[string]float64)
+	counts := make(map[string]int)
+	for quadKey, value := range values {
+		if !validQuadKey(quadKey) || len(quadKey) < levels {
+			continue
+		}
+		ancestor := quadKey[:len(quadKey)-levels]
+		if agg == "max" {
+			if counts[ancestor] == 0 {
+				result[ancestor] = value
+			} else {
+				result[ancestor] = math.Max(result[ancestor], value)
+			}
+		} else {
+			result[ancestor] += value
+		}
+		counts[ancestor]++
+	}
+
+	if agg == "mean" {
+		for ancestor, count := range counts {
+			result[ancestor] /= float64(count)
+		}
+	}
+	return result
+}
+
+/// <summary>
+/// Smooths a single-level tile-value grid with a box blur: each tile with a
+/// value is replaced by the mean over the (2r+1) x (2r+1) window of tiles
+/// centered on it, with tiles that have no value counting as zero. The
+/// window follows the edge rules of Tile.Neighbor: it wraps east-west
+/// across the antimeridian through NormalizeTileX (covering each column at
+/// most once when it is wider than the map) but not across the poles, where
+/// rows beyond the top or bottom of the map are left out of the mean, so
+/// tiles near the map's top and bottom edges average over fewer tiles.
+/// Keys that are invalid or not at levelOfDetail are ignored.
+/// </summary>
+/// <param name="values">Values keyed by QuadKey.</param>
+/// <param name="levelOfDetail">Level of detail of the grid.</param>
+/// <param name="radiusTiles">Window radius r, in tiles; 0 leaves values unchanged.</param>
+/// <returns>The smoothed values, keyed by the QuadKeys of the input tiles,
+/// or nil if radiusTiles is negative or the level is above MaxLevel.</returns>
+func TileBoxBlur(values map[string]float64, levelOfDetail uint, radiusTiles int) map[string]float64 {
+	if radiusTiles < 0 || validLevel(levelOfDetail) != nil {
+		return nil
+	}
+	tileCount := tilesPerSide(levelOfDetail)
+	grid := make(map[[2]int]float64, len(values))
+	for quadKey, value := range values {
+		if uint(len(quadKey)) != levelOfDetail || !validQuadKey(quadKey) {
+			continue
+		}
+		tileX, tileY, _ := QuadKeyToTileXY(quadKey)
+		grid[[2]int{tileX, tileY}] = value
+	}
+
+	if radiusTiles > tileCount {
+		// A wider window covers the same tiles.
+		radiusTiles = tileCount
+	}
+	columns := 2*radiusTiles + 1
+	if columns > tileCount {
+		columns = tileCount
+	}
+	blurred := make(map[string]float64, len(grid))
+	for tile := range grid {
+		sum, count := 0.0, 0
+		minY := ClampTileY(tile[1]-radiusTiles, levelOfDetail)
+		maxY := ClampTileY(tile[1]+radiusTiles, levelOfDetail)
+		westX := NormalizeTileX(tile[0]-radiusTiles, levelOfDetail)
+		if columns == tileCount {
+			westX = 0
+		}
+		for tileY := minY; tileY <= maxY; tileY++ {
+			for i := 0; i < columns; i++ {
+				tileX := NormalizeTileX(westX+i, levelOfDetail)
+				sum += grid[[2]int{tileX, tileY}]
+				count++
+			}
+		}
+		blurred[TileXYToQuadKey(tile[0], tile[1], levelOfDetail)] = sum / float64(count)
+	}
+	return blurred
+}
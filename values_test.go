@@ -0,0 +1,50 @@
+// Quadkeys project values_test.go
+package Quadkeys
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTileBoxBlur(t *testing.T) {
+	values := map[string]float64{
+		TileXYToQuadKey(4, 4, 3): 9,
+		TileXYToQuadKey(5, 4, 3): 0,
+		TileXYToQuadKey(0, 0, 3): 4,
+		TileXYToQuadKey(7, 0, 3): 2,
+	}
+	blurred := TileBoxBlur(values, 3, 1)
+	tests := []struct {
+		tileX, tileY int
+		want         float64
+	}{
+		{4, 4, 9.0 / 9},
+		{5, 4, 9.0 / 9},
+		// Top row: the window loses the row above and wraps east-west.
+		{0, 0, 6.0 / 6},
+		{7, 0, 6.0 / 6},
+	}
+	for _, test := range tests {
+		if got := blurred[TileXYToQuadKey(test.tileX, test.tileY, 3)]; math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("tile (%d, %d) blurred to %v, want %v", test.tileX, test.tileY, got, test.want)
+		}
+	}
+	if len(blurred) != len(values) {
+		t.Errorf("TileBoxBlur returned %d tiles, want %d", len(blurred), len(values))
+	}
+}
+
+func TestTileBoxBlurLargeRadius(t *testing.T) {
+	values := map[string]float64{"0": 4, "3": 8}
+	for _, radius := range []int{1, 2, 1 << 40, math.MaxInt} {
+		blurred := TileBoxBlur(values, 1, radius)
+		for quadKey := range values {
+			if got := blurred[quadKey]; got != 3 {
+				t.Errorf("radius %d: %q blurred to %v, want the world mean 3", radius, quadKey, got)
+			}
+		}
+	}
+	if TileBoxBlur(values, 1, -1) != nil || TileBoxBlur(values, MaxLevel+1, 1) != nil {
+		t.Error("TileBoxBlur accepted a negative radius or a level above MaxLevel")
+	}
+}